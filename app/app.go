@@ -17,13 +17,21 @@ func GroupCommands() *cobra.Command {
 
 	util.ApplyDefaultFlags(getUsersCmd)
 	util.ApplyDefaultFlags(createUserCmd)
+	util.ApplyDefaultFlags(updateUserCmd)
 	util.ApplyDefaultFlags(deleteUserCmd)
 	util.ApplyDefaultFlags(getServersCmd)
+	util.ApplyDefaultFlags(createServerCmd)
+	util.ApplyDefaultFlags(updateServerBuildCmd)
+	util.ApplyDefaultFlags(updateServerDetailsCmd)
+	util.ApplyDefaultFlags(updateServerStartupCmd)
 	util.ApplyDefaultFlags(suspendServerCmd)
 	util.ApplyDefaultFlags(unsuspendServerCmd)
 	util.ApplyDefaultFlags(reinstallServerCmd)
 	util.ApplyDefaultFlags(deleteServerCmd)
 	util.ApplyDefaultFlags(getNodesCmd)
+	util.ApplyDefaultFlags(createNodeCmd)
+	util.ApplyDefaultFlags(updateNodeCmd)
+	util.ApplyDefaultFlags(setNodeMaintenanceCmd)
 	util.ApplyDefaultFlags(getNodeConfigCmd)
 	util.ApplyDefaultFlags(getNodeAllocationsCmd)
 	util.ApplyDefaultFlags(createAllocationsCmd)
@@ -33,18 +41,43 @@ func GroupCommands() *cobra.Command {
 	util.ApplyDefaultFlags(deleteLocationCmd)
 	util.ApplyDefaultFlags(getNestsCmd)
 	util.ApplyDefaultFlags(getNestEggsCmd)
+	util.ApplyDefaultFlags(exportCmd)
+	util.ApplyDefaultFlags(importCmd)
 
 	util.ApplyFilterFlags(getUsersCmd)
 	util.ApplyFilterFlags(getServersCmd)
 	util.ApplyFilterFlags(getNodesCmd)
+	util.ApplyFilterFlags(getNodeAllocationsCmd)
 	util.ApplyFilterFlags(getLocationsCmd)
 	util.ApplyFilterFlags(getNestsCmd)
 	util.ApplyFilterFlags(getNestEggsCmd)
 
 	util.ApplyDataFlags(createUserCmd)
+	util.ApplyDataFlags(updateUserCmd)
+	util.ApplyDataFlags(createServerCmd)
+	util.ApplyDataFlags(updateServerBuildCmd)
+	util.ApplyDataFlags(updateServerDetailsCmd)
+	util.ApplyDataFlags(updateServerStartupCmd)
+	util.ApplyDataFlags(createNodeCmd)
+	util.ApplyDataFlags(updateNodeCmd)
 	util.ApplyDataFlags(createAllocationsCmd)
 	util.ApplyDataFlags(createLocationCmd)
 
+	util.ApplyWaitFlags(suspendServerCmd)
+	util.ApplyWaitFlags(reinstallServerCmd)
+
+	util.ApplyBulkFlags(deleteUserCmd)
+	util.ApplyBulkFlags(suspendServerCmd)
+	util.ApplyBulkFlags(unsuspendServerCmd)
+	util.ApplyBulkFlags(reinstallServerCmd)
+	util.ApplyBulkFlags(deleteServerCmd)
+
+	util.ApplyConfirmFlags(deleteUserCmd)
+	util.ApplyConfirmFlags(reinstallServerCmd)
+	util.ApplyConfirmFlags(deleteServerCmd)
+	util.ApplyConfirmFlags(deleteAllocationCmd)
+	util.ApplyConfirmFlags(deleteLocationCmd)
+
 	getUsersCmd.Flags().Int("id", 0, "the id of the user")
 	getUsersCmd.Flags().String("external", "", "the external id of the user")
 	getUsersCmd.Flags().String("username", "", "filter by user username")
@@ -56,6 +89,8 @@ func GroupCommands() *cobra.Command {
 	getServersCmd.Flags().String("desc", "", "filter by server description")
 	getServersCmd.Flags().String("uuid", "", "filter by server uuid")
 	getServersCmd.Flags().String("image", "", "filter by server docker image")
+	createServerCmd.Flags().StringArray("env", nil, "set an environment variable (key=value)")
+	updateServerStartupCmd.Flags().StringArray("env", nil, "set an environment variable (key=value)")
 	deleteServerCmd.Flags().Bool("force", false, "force delete the server")
 	getNodesCmd.Flags().Int("id", 0, "the id of the node")
 	getNodesCmd.Flags().String("name", "", "filter by the node name")
@@ -65,16 +100,29 @@ func GroupCommands() *cobra.Command {
 	getLocationsCmd.Flags().Int("id", 0, "the id of the location")
 	getNestsCmd.Flags().Int("id", 0, "the id of the nest")
 	getNestEggsCmd.Flags().Int("id", 0, "the id of the egg")
+	exportCmd.Flags().String("dir", "", "the directory to export to")
+	exportCmd.Flags().String("format", "yaml", "the format of the export files (yaml, json)")
+	importCmd.Flags().String("dir", "", "the directory to import from")
+	importCmd.Flags().String("mapping", "", "the path to the id mapping file")
+	importCmd.Flags().Bool("skip-scripts", false, "skip the egg install scripts for servers")
 
 	cmd.AddCommand(getUsersCmd)
 	cmd.AddCommand(createUserCmd)
+	cmd.AddCommand(updateUserCmd)
 	cmd.AddCommand(deleteUserCmd)
 	cmd.AddCommand(getServersCmd)
+	cmd.AddCommand(createServerCmd)
+	cmd.AddCommand(updateServerBuildCmd)
+	cmd.AddCommand(updateServerDetailsCmd)
+	cmd.AddCommand(updateServerStartupCmd)
 	cmd.AddCommand(suspendServerCmd)
 	cmd.AddCommand(unsuspendServerCmd)
 	cmd.AddCommand(reinstallServerCmd)
 	cmd.AddCommand(deleteServerCmd)
 	cmd.AddCommand(getNodesCmd)
+	cmd.AddCommand(createNodeCmd)
+	cmd.AddCommand(updateNodeCmd)
+	cmd.AddCommand(setNodeMaintenanceCmd)
 	cmd.AddCommand(getNodeConfigCmd)
 	cmd.AddCommand(getNodeAllocationsCmd)
 	cmd.AddCommand(createAllocationsCmd)
@@ -84,6 +132,8 @@ func GroupCommands() *cobra.Command {
 	cmd.AddCommand(deleteLocationCmd)
 	cmd.AddCommand(getNestsCmd)
 	cmd.AddCommand(getNestEggsCmd)
+	cmd.AddCommand(exportCmd)
+	cmd.AddCommand(importCmd)
 
 	return cmd
 }
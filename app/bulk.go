@@ -0,0 +1,125 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pteropackages/soar/ptero"
+	"github.com/pteropackages/soar/util"
+	"github.com/spf13/cobra"
+)
+
+var serverAliases = map[string]string{"image": "container.image"}
+
+func serverTargets(cmd *cobra.Command, app *ptero.Application, args []string) ([]int, error) {
+	selector, raw, err := util.ParseBulkFlags(cmd.Flags(), args)
+	if err != nil {
+		return nil, err
+	}
+	if selector == nil {
+		return parseIDs("server", raw)
+	}
+
+	servers, _, err := app.ListServers(cmd.Context(), &ptero.ListOptions{All: true})
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int
+	for _, s := range servers {
+		ok, err := selector.Matches(s, serverAliases)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			ids = append(ids, s.ID)
+		}
+	}
+
+	return ids, nil
+}
+
+func userTargets(cmd *cobra.Command, app *ptero.Application, args []string) ([]int, error) {
+	selector, raw, err := util.ParseBulkFlags(cmd.Flags(), args)
+	if err != nil {
+		return nil, err
+	}
+	if selector == nil {
+		return parseIDs("user", raw)
+	}
+
+	users, _, err := app.ListUsers(cmd.Context(), &ptero.ListOptions{All: true})
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int
+	for _, u := range users {
+		ok, err := selector.Matches(u, nil)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			ids = append(ids, u.ID)
+		}
+	}
+
+	return ids, nil
+}
+
+func parseIDs(kind string, raw []string) ([]int, error) {
+	ids := make([]int, len(raw))
+	for i, r := range raw {
+		id, err := strconv.Atoi(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s id '%s'", kind, r)
+		}
+		ids[i] = id
+	}
+
+	return ids, nil
+}
+
+// confirmBulk prompts for the number of resources before a destructive action is
+// run on all of them.
+func confirmBulk(cmd *cobra.Command, action, kind string, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = strconv.Itoa(id)
+	}
+
+	prompt := fmt.Sprintf("%s %d %s(s) with the ids %s?", action, len(ids), kind, strings.Join(names, ", "))
+	return util.Confirm(cmd.Flags(), prompt, strconv.Itoa(len(ids)))
+}
+
+// runBulk runs the action on each of the IDs and exits with a non-zero status code
+// if any of them failed.
+func runBulk(cmd *cobra.Command, kind string, ids []int, action func(ctx context.Context, id int) error) {
+	if len(ids) == 0 {
+		log.Warn("no %ss matched the selector", kind)
+		return
+	}
+
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = strconv.Itoa(id)
+	}
+
+	// request logs would break up the results
+	log.Quiet = true
+
+	parallel, _ := cmd.Flags().GetInt("parallel")
+	ok := util.RunBulk(log, kind, names, parallel, func(i int) error {
+		return action(cmd.Context(), ids[i])
+	})
+	if !ok {
+		os.Exit(1)
+	}
+}
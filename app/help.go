@@ -9,12 +9,94 @@ var createUserHelp = "Creates a user account on the panel using the data provide
 	"The username, email, first_name, last_name and root_admin fields are required.\n" +
 	"The external_id and password fields are optional and are omitted by default."
 
+var updateUserHelp = "Updates a user account on the panel using the data provided from one of the following options:\n" +
+	"'--data source' - takes a set of key-value pairs for arguments (e.g. \"email=new@example.com root_admin=true\")\n" +
+	"'--data-file file' - takes a file path to a JSON file with the data fields\n" +
+	"'--data-json source' - takes a raw JSON data input\n\n" +
+	"All fields are optional; fields that are not specified are kept from the current user.\n" +
+	"The available fields are username, email, external_id, first_name, last_name, language, root_admin and password."
+
 var getServersHelp = "Gets a list of servers from the panel (supports the --id flag)."
 
+var createServerHelp = "Creates a server on the panel using the data provided from one of the following options:\n" +
+	"'--data source' - takes a set of key-value pairs for arguments (e.g. \"name=example user=1 nest=1 egg=5 limits.memory=1024\")\n" +
+	"'--data-file file' - takes a file path to a JSON file with the data fields\n" +
+	"'--data-json source' - takes a raw JSON data input\n\n" +
+	"The name, user, nest and egg fields are required. The server can either be deployed to a specific\n" +
+	"allocation with allocation.default (and optionally allocation.additional), or to a free allocation\n" +
+	"in one or more locations with deploy.locations (and optionally deploy.dedicated_ip and deploy.port_range).\n\n" +
+	"The docker_image, startup and environment fields default to the values from the egg.\n" +
+	"Environment variables can be set with the '--env key=value' flag, which can be repeated."
+
+var updateServerBuildHelp = "Updates the build configuration of a server using the data provided from one of the following options:\n" +
+	"'--data source' - takes a set of key-value pairs for arguments (e.g. \"limits.memory=4096 feature_limits.backups=2\")\n" +
+	"'--data-file file' - takes a file path to a JSON file with the data fields\n" +
+	"'--data-json source' - takes a raw JSON data input\n\n" +
+	"All fields are optional; fields that are not specified are kept from the current server.\n" +
+	"The available fields are allocation, limits.memory, limits.swap, limits.disk, limits.io, limits.cpu,\n" +
+	"limits.threads, limits.oom_disabled, feature_limits.databases, feature_limits.allocations,\n" +
+	"feature_limits.backups, add_allocations and remove_allocations."
+
+var updateServerDetailsHelp = "Updates the details of a server using the data provided from one of the following options:\n" +
+	"'--data source' - takes a set of key-value pairs for arguments (e.g. \"name=example user=2\")\n" +
+	"'--data-file file' - takes a file path to a JSON file with the data fields\n" +
+	"'--data-json source' - takes a raw JSON data input\n\n" +
+	"All fields are optional; fields that are not specified are kept from the current server.\n" +
+	"The available fields are name, user, external_id and description."
+
+var updateServerStartupHelp = "Updates the startup configuration of a server using the data provided from one of the following options:\n" +
+	"'--data source' - takes a set of key-value pairs for arguments (e.g. \"image=ghcr.io/pterodactyl/yolks:java_17\")\n" +
+	"'--data-file file' - takes a file path to a JSON file with the data fields\n" +
+	"'--data-json source' - takes a raw JSON data input\n\n" +
+	"All fields are optional; fields that are not specified are kept from the current server.\n" +
+	"The available fields are startup, egg, image, skip_scripts and environment (JSON only).\n" +
+	"Environment variables can also be set with the '--env key=value' flag, which can be repeated."
+
 var getNodesHelp = "Gets a list of nodes from the panel (supports the --id flag)."
 
+var createNodeHelp = "Creates a node on the panel using the data provided from one of the following options:\n" +
+	"'--data source' - takes a set of key-value pairs for arguments (e.g. \"name=example location_id=1 fqdn=node.example.com\")\n" +
+	"'--data-file file' - takes a file path to a JSON file with the data fields\n" +
+	"'--data-json source' - takes a raw JSON data input\n\n" +
+	"The name, location_id and fqdn fields are required. The other fields are optional and default to:\n" +
+	"public=true scheme=https behind_proxy=false maintenance_mode=false memory=0 memory_overallocate=0\n" +
+	"disk=0 disk_overallocate=0 upload_size=100 daemon_listen=8080 daemon_sftp=2022\n" +
+	"daemon_base=/var/lib/pterodactyl/volumes"
+
+var updateNodeHelp = "Updates a node on the panel using the data provided from one of the following options:\n" +
+	"'--data source' - takes a set of key-value pairs for arguments (e.g. \"memory=16384 memory_overallocate=10\")\n" +
+	"'--data-file file' - takes a file path to a JSON file with the data fields\n" +
+	"'--data-json source' - takes a raw JSON data input\n\n" +
+	"All fields are optional; fields that are not specified are kept from the current node.\n" +
+	"The available fields are name, description, location_id, public, fqdn, scheme, behind_proxy,\n" +
+	"maintenance_mode, memory, memory_overallocate, disk, disk_overallocate, upload_size,\n" +
+	"daemon_listen, daemon_sftp and daemon_base."
+
 var getLocationsHelp = "Gets a list of node locations from the panel (supports --id flag)."
 
 var getNestsHelp = "Gets a list of nests from the panel (supports the --id flag)."
 
 var getNestEggsHelp = "Gets a list of eggs for a specified nest from the panel (supports the --id flag)."
+
+var exportHelp = "Exports the locations, nodes (with their allocations), users, nests (with their eggs) and servers\n" +
+	"of the panel to separate files in the directory. Each file has a version field and a data field with the\n" +
+	"resources as they are returned by the API. The files can be edited before they are imported, for example\n" +
+	"to change the FQDN of a node."
+
+var importHelp = "Imports the files from 'soar app export' into the panel. Locations, nodes, allocations, users and\n" +
+	"servers are created, and the IDs of the source panel are mapped to the IDs of the new resources in a\n" +
+	"mapping file (mapping.yml in the export directory by default). Resources that are already in the mapping\n" +
+	"are skipped, and resources that already exist are matched by the location short code, node name, user\n" +
+	"email, allocation IP and port, and server external ID, so the import can be run again if it fails part\n" +
+	"way.\n\n" +
+	"Eggs can't be created through the API, so they are matched by their nest and egg names. Eggs that don't\n" +
+	"exist on the panel must be imported first, or added to the 'eggs' section of the mapping file. A user\n" +
+	"with the same username but a different email stops the import; add the pair to the 'users' section of\n" +
+	"the mapping file if they are the same person. Users are created without passwords, and servers are\n" +
+	"created with their default allocation only."
+
+var bulkHelp = "To run on multiple resources, use '--selector key=value,...' to match them by their fields\n" +
+	"(the JSON field names, with dots for nested fields, e.g. \"node=3,container.image=...\"), or\n" +
+	"'--from-file path' to read one ID per line from a file ('-' or an ID argument of '-' reads stdin).\n" +
+	"The '--parallel n' flag runs on up to n resources at once. The result for each resource is printed,\n" +
+	"and the exit code is non-zero if any of them failed."
@@ -1,12 +1,14 @@
 package app
 
 import (
-	"bytes"
 	"fmt"
+	"os"
+	"strconv"
 
 	"github.com/pteropackages/soar/config"
 	"github.com/pteropackages/soar/http"
 	"github.com/pteropackages/soar/input"
+	"github.com/pteropackages/soar/ptero"
 	"github.com/pteropackages/soar/util"
 	"github.com/spf13/cobra"
 )
@@ -19,33 +21,36 @@ var getLocationsCmd = &cobra.Command{
 		log.ApplyFlags(cmd.Flags())
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		path := "/api/application/locations"
-		id, _ := cmd.Flags().GetInt("id")
-		if id != 0 {
-			path += fmt.Sprintf("/%d", id)
-		}
-
-		ctx := http.New(cfg, &cfg.Application, log)
-		req := ctx.Request("GET", path, nil)
-		res, err := ctx.ExecuteWithFlags(req, cmd.Flags())
-		if err != nil {
-			log.WithError(err)
-			return
-		}
-
+		app := ptero.NewApplication(http.New(cfg, &cfg.Application, log))
 		var buf []byte
 
-		if id == 0 {
-			buf, err = http.HandleDataResponse(res, cfg)
+		if id, _ := cmd.Flags().GetInt("id"); id != 0 {
+			var location *ptero.Location
+			location, err = app.GetLocation(cmd.Context(), id)
+			if err != nil {
+				http.HandleError(err, cfg, log)
+				return
+			}
+
+			buf, err = http.HandleItem("location", location, cfg)
 		} else {
-			buf, err = http.HandleItemResponse(res, cfg)
+			var locations []*ptero.Location
+			var meta *ptero.Meta
+			locations, meta, err = app.ListLocations(cmd.Context(), util.ParseListOptions(cmd.Flags()))
+			if err != nil {
+				http.HandleError(err, cfg, log)
+				return
+			}
+
+			buf, err = http.HandleList("location", locations, meta, cfg)
 		}
 		if err != nil {
 			log.WithError(err)
@@ -63,75 +68,32 @@ var createLocationCmd = &cobra.Command{
 		log.ApplyFlags(cmd.Flags())
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		var payload []byte
-		data, _ := cmd.Flags().GetString("data")
-		file, _ := cmd.Flags().GetString("file")
-		js, _ := cmd.Flags().GetString("json")
-
-		switch {
-		case data != "":
-			m, err := input.Parse(data)
-			if err != nil {
-				log.WithError(err).Error("failed to parse data input")
-				return
-			}
-
-			payload, err = input.Marshal(input.Definition{
-				"short": input.StringNode,
-				"long":  input.StringNode,
-			}, m)
-			if err != nil {
-				log.WithError(err).Error("failed to parse data input")
-				return
-			}
-		case file != "":
-			v, err := util.SafeReadFile(file)
-			if err != nil {
-				log.WithError(err)
-				return
-			}
-
-			payload, err = util.ValidateSchema(v, struct {
-				Short string `json:"short"`
-				Long  string `json:"long"`
-			}{})
-			if err != nil {
-				log.WithError(err).Error("failed to parse json input")
-				return
-			}
-		case js != "":
-			payload, err = util.ValidateSchema([]byte(js), struct {
-				Short string `json:"short"`
-				Long  string `json:"long"`
-			}{})
-			if err != nil {
-				log.WithError(err).Error("failed to parse json input")
-				return
-			}
-		default:
-			log.Error("no data source provided").Error("'--data', '--data-file' or '--data-json' must be specified")
+		var fields ptero.CreateLocationDescriptor
+		err = util.ReadDataFlags(cmd.Flags(), input.Definition{
+			"short": input.StringNode,
+			"long":  input.StringNode,
+		}, &fields)
+		if err != nil {
+			log.WithError(err)
 			return
 		}
 
-		body := bytes.Buffer{}
-		body.Write(payload)
-
-		ctx := http.New(cfg, &cfg.Application, log)
-		req := ctx.Request("POST", "/api/application/locations", &body)
-		res, err := ctx.Execute(req)
+		app := ptero.NewApplication(http.New(cfg, &cfg.Application, log))
+		location, err := app.CreateLocation(cmd.Context(), fields)
 		if err != nil {
-			log.WithError(err)
+			http.HandleError(err, cfg, log)
 			return
 		}
 
-		buf, err := http.HandleItemResponse(res, cfg)
+		buf, err := http.HandleItem("location", location, cfg)
 		if err != nil {
 			log.WithError(err)
 			return
@@ -142,7 +104,7 @@ var createLocationCmd = &cobra.Command{
 }
 
 var deleteLocationCmd = &cobra.Command{
-	Use:   "locations:delete id",
+	Use:   "locations:delete id [-y]",
 	Short: "deletes a location",
 	Long:  "Deletes a location",
 	Run: func(cmd *cobra.Command, args []string) {
@@ -152,18 +114,36 @@ var deleteLocationCmd = &cobra.Command{
 			return
 		}
 
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			log.Error("invalid location id '%s'", args[0])
+			return
+		}
+
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		ctx := http.New(cfg, &cfg.Application, log)
-		req := ctx.Request("DELETE", "/api/application/locations/"+args[0], nil)
-		if _, err := ctx.Execute(req); err != nil {
+		app := ptero.NewApplication(http.New(cfg, &cfg.Application, log))
+		location, err := app.GetLocation(cmd.Context(), id)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		prompt := fmt.Sprintf("delete location '%s' (%s)?", location.Short, location.Long)
+		if err = util.Confirm(cmd.Flags(), prompt, location.Short); err != nil {
 			log.WithError(err)
+			os.Exit(1)
+		}
+
+		if err = app.DeleteLocation(cmd.Context(), id); err != nil {
+			http.HandleError(err, cfg, log)
 		}
 	},
 }
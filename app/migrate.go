@@ -0,0 +1,138 @@
+package app
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/pteropackages/soar/config"
+	"github.com/pteropackages/soar/http"
+	"github.com/pteropackages/soar/migrate"
+	"github.com/pteropackages/soar/ptero"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export --dir path [--format yaml|json]",
+	Short: "exports the panel state to files",
+	Long:  exportHelp,
+	Run: func(cmd *cobra.Command, _ []string) {
+		log.ApplyFlags(cmd.Flags())
+
+		dir, _ := cmd.Flags().GetString("dir")
+		if dir == "" {
+			log.Error("an export directory must be specified with the --dir flag")
+			return
+		}
+
+		format, _ := cmd.Flags().GetString("format")
+		if format != "yaml" && format != "json" {
+			log.Error("invalid export format '%s'; must be yaml or json", format)
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		app := ptero.NewApplication(http.New(cfg, &cfg.Application, log))
+		snapshot, err := migrate.Export(cmd.Context(), app)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		if err = snapshot.Save(dir, format); err != nil {
+			log.Error("failed to save export:").WithError(err)
+			return
+		}
+
+		log.Ignore().Info("exported %d location(s), %d node(s), %d user(s), %d nest(s) and %d server(s) to %s",
+			len(snapshot.Locations), len(snapshot.Nodes), len(snapshot.Users), len(snapshot.Nests),
+			len(snapshot.Servers), dir)
+	},
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import --dir path [--mapping file] [--skip-scripts]",
+	Short: "imports an exported panel state",
+	Long:  importHelp,
+	Run: func(cmd *cobra.Command, _ []string) {
+		log.ApplyFlags(cmd.Flags())
+
+		dir, _ := cmd.Flags().GetString("dir")
+		if dir == "" {
+			log.Error("an export directory must be specified with the --dir flag")
+			return
+		}
+
+		path, _ := cmd.Flags().GetString("mapping")
+		if path == "" {
+			path = filepath.Join(dir, "mapping.yml")
+		}
+
+		snapshot, err := migrate.Load(dir)
+		if err != nil {
+			log.Error("failed to load export:").WithError(err)
+			return
+		}
+
+		mapping, err := migrate.LoadMapping(path)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		skip, _ := cmd.Flags().GetBool("skip-scripts")
+		importer := &migrate.Importer{
+			App:         ptero.NewApplication(http.New(cfg, &cfg.Application, log)),
+			Mapping:     mapping,
+			SkipScripts: skip,
+			OnChange: func(action, kind, name string, from, to int) error {
+				log.Line("%s %s %s (%d => %d)", action, kind, name, from, to)
+				if cfg.Http.DryRun {
+					return nil
+				}
+
+				return mapping.Save(path)
+			},
+		}
+
+		err = importer.Run(cmd.Context(), snapshot)
+		if errors.Is(err, http.ErrDryRun) {
+			log.Info("the import stops at the first change in dry run mode, as later changes depend on its id")
+			return
+		}
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			log.Info("the id mapping was saved to %s, run the command again to continue the import", path)
+			os.Exit(1)
+		}
+
+		if cfg.Http.DryRun {
+			log.Ignore().Info("dry run complete, no changes were made")
+			return
+		}
+
+		if err = mapping.Save(path); err != nil {
+			log.Error("failed to save the id mapping:").WithError(err)
+			return
+		}
+
+		log.Ignore().Info("import complete, the id mapping was saved to %s", path)
+	},
+}
@@ -1,10 +1,11 @@
 package app
 
 import (
-	"fmt"
+	"strconv"
 
 	"github.com/pteropackages/soar/config"
 	"github.com/pteropackages/soar/http"
+	"github.com/pteropackages/soar/ptero"
 	"github.com/pteropackages/soar/util"
 	"github.com/spf13/cobra"
 )
@@ -17,33 +18,36 @@ var getNestsCmd = &cobra.Command{
 		log.ApplyFlags(cmd.Flags())
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		path := "/api/application/nests"
-		id, _ := cmd.Flags().GetInt("id")
-		if id != 0 {
-			path += fmt.Sprintf("/%d", id)
-		}
-
-		ctx := http.New(cfg, &cfg.Application, log)
-		req := ctx.Request("GET", path, nil)
-		res, err := ctx.ExecuteWithFlags(req, cmd.Flags())
-		if err != nil {
-			log.WithError(err)
-			return
-		}
-
+		app := ptero.NewApplication(http.New(cfg, &cfg.Application, log))
 		var buf []byte
 
-		if id == 0 {
-			buf, err = http.HandleDataResponse(res, cfg)
+		if id, _ := cmd.Flags().GetInt("id"); id != 0 {
+			var nest *ptero.Nest
+			nest, err = app.GetNest(cmd.Context(), id)
+			if err != nil {
+				http.HandleError(err, cfg, log)
+				return
+			}
+
+			buf, err = http.HandleItem("nest", nest, cfg)
 		} else {
-			buf, err = http.HandleItemResponse(res, cfg)
+			var nests []*ptero.Nest
+			var meta *ptero.Meta
+			nests, meta, err = app.ListNests(cmd.Context(), util.ParseListOptions(cmd.Flags()))
+			if err != nil {
+				http.HandleError(err, cfg, log)
+				return
+			}
+
+			buf, err = http.HandleList("nest", nests, meta, cfg)
 		}
 		if err != nil {
 			log.WithError(err)
@@ -66,34 +70,43 @@ var getNestEggsCmd = &cobra.Command{
 			return
 		}
 
-		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		nest, err := strconv.Atoi(args[0])
 		if err != nil {
-			config.HandleError(err, log)
+			log.Error("invalid nest id '%s'", args[0])
 			return
 		}
-		cfg.ApplyFlags(cmd.Flags())
 
-		path := fmt.Sprintf("/api/application/nests/%s/eggs", args[0])
-		id, _ := cmd.Flags().GetInt("id")
-		if id != 0 {
-			path += fmt.Sprintf("/%d", id)
-		}
-
-		ctx := http.New(cfg, &cfg.Application, log)
-		req := ctx.Request("GET", path, nil)
-		res, err := ctx.ExecuteWithFlags(req, cmd.Flags())
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
-			log.WithError(err)
+			config.HandleError(err, log)
 			return
 		}
+		cfg.ApplyFlags(cmd.Flags())
 
+		app := ptero.NewApplication(http.New(cfg, &cfg.Application, log))
 		var buf []byte
 
-		if id == 0 {
-			buf, err = http.HandleDataResponse(res, cfg)
+		if id, _ := cmd.Flags().GetInt("id"); id != 0 {
+			var egg *ptero.Egg
+			egg, err = app.GetNestEgg(cmd.Context(), nest, id)
+			if err != nil {
+				http.HandleError(err, cfg, log)
+				return
+			}
+
+			buf, err = http.HandleItem("egg", egg, cfg)
 		} else {
-			buf, err = http.HandleItemResponse(res, cfg)
+			var eggs []*ptero.Egg
+			var meta *ptero.Meta
+			eggs, meta, err = app.ListNestEggs(cmd.Context(), nest, util.ParseListOptions(cmd.Flags()))
+			if err != nil {
+				http.HandleError(err, cfg, log)
+				return
+			}
+
+			buf, err = http.HandleList("egg", eggs, meta, cfg)
 		}
 		if err != nil {
 			log.WithError(err)
@@ -1,14 +1,14 @@
 package app
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
-	"strings"
+	"os"
+	"strconv"
 
 	"github.com/pteropackages/soar/config"
 	"github.com/pteropackages/soar/http"
 	"github.com/pteropackages/soar/input"
+	"github.com/pteropackages/soar/ptero"
 	"github.com/pteropackages/soar/util"
 	"github.com/spf13/cobra"
 )
@@ -21,34 +21,107 @@ var getNodesCmd = &cobra.Command{
 		log.ApplyFlags(cmd.Flags())
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		single, query, err := parseNodeQuery(cmd)
+		app := ptero.NewApplication(http.New(cfg, &cfg.Application, log))
+		var buf []byte
+
+		if id, _ := cmd.Flags().GetInt("id"); id != 0 {
+			var node *ptero.Node
+			node, err = app.GetNode(cmd.Context(), id)
+			if err != nil {
+				http.HandleError(err, cfg, log)
+				return
+			}
+
+			buf, err = http.HandleItem("node", node, cfg)
+		} else {
+			opts := util.ParseListOptions(cmd.Flags())
+			filters := map[string]string{
+				"name":  "name",
+				"uuid":  "uuid",
+				"fqdn":  "fqdn",
+				"token": "daemon_token_id",
+			}
+			for flag, key := range filters {
+				if val, _ := cmd.Flags().GetString(flag); val != "" {
+					opts.Filters[key] = val
+				}
+			}
+
+			var nodes []*ptero.Node
+			var meta *ptero.Meta
+			nodes, meta, err = app.ListNodes(cmd.Context(), opts)
+			if err != nil {
+				http.HandleError(err, cfg, log)
+				return
+			}
+
+			buf, err = http.HandleList("node", nodes, meta, cfg)
+		}
 		if err != nil {
-			log.Error("command error:").WithError(err)
+			log.WithError(err)
 			return
 		}
 
-		ctx := http.New(cfg, &cfg.Application, log)
-		req := ctx.Request("GET", "/api/application/nodes"+query, nil)
-		res, err := ctx.ExecuteWithFlags(req, cmd.Flags())
+		log.LineB(buf)
+	},
+}
+
+var createNodeCmd = &cobra.Command{
+	Use:   "nodes:create --data[-file | -json] source",
+	Short: "creates a node",
+	Long:  createNodeHelp,
+	Run: func(cmd *cobra.Command, _ []string) {
+		log.ApplyFlags(cmd.Flags())
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
-			log.WithError(err)
+			config.HandleError(err, log)
 			return
 		}
+		cfg.ApplyFlags(cmd.Flags())
 
-		var buf []byte
+		fields := ptero.NewNodeDescriptor()
+		err = util.ReadPartialDataFlags(cmd.Flags(), input.Definition{
+			"name":                input.StringNode,
+			"description":         input.StringNode,
+			"location_id":         input.NumberNode,
+			"public":              input.BoolNode,
+			"fqdn":                input.StringNode,
+			"scheme":              input.StringNode,
+			"behind_proxy":        input.BoolNode,
+			"maintenance_mode":    input.BoolNode,
+			"memory":              input.NumberNode,
+			"memory_overallocate": input.NumberNode,
+			"disk":                input.NumberNode,
+			"disk_overallocate":   input.NumberNode,
+			"upload_size":         input.NumberNode,
+			"daemon_listen":       input.NumberNode,
+			"daemon_sftp":         input.NumberNode,
+			"daemon_base":         input.StringNode,
+		}, &fields)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
 
-		if single {
-			buf, err = http.HandleItemResponse(res, cfg)
-		} else {
-			buf, err = http.HandleDataResponse(res, cfg)
+		app := ptero.NewApplication(http.New(cfg, &cfg.Application, log))
+		node, err := app.CreateNode(cmd.Context(), fields)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
 		}
+
+		buf, err := http.HandleItem("node", node, cfg)
 		if err != nil {
 			log.WithError(err)
 			return
@@ -58,44 +131,135 @@ var getNodesCmd = &cobra.Command{
 	},
 }
 
-func parseNodeQuery(cmd *cobra.Command) (bool, string, error) {
-	var query strings.Builder
-	var params []string
-	single := false
-	flags := cmd.Flags()
+var updateNodeCmd = &cobra.Command{
+	Use:   "nodes:update id --data[-file | -json] source",
+	Short: "updates a node",
+	Long:  updateNodeHelp,
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"id"}); err != nil {
+			log.WithError(err)
+			return
+		}
 
-	if id, _ := flags.GetInt("id"); id != 0 {
-		single = true
-		query.WriteString(fmt.Sprintf("/%d", id))
-	}
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			log.Error("invalid node id '%s'", args[0])
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		app := ptero.NewApplication(http.New(cfg, &cfg.Application, log))
+		node, err := app.GetNode(cmd.Context(), id)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		fields := node.Descriptor()
+		err = util.ReadPartialDataFlags(cmd.Flags(), input.Definition{
+			"name":                input.StringNode,
+			"description":         input.StringNode,
+			"location_id":         input.NumberNode,
+			"public":              input.BoolNode,
+			"fqdn":                input.StringNode,
+			"scheme":              input.StringNode,
+			"behind_proxy":        input.BoolNode,
+			"maintenance_mode":    input.BoolNode,
+			"memory":              input.NumberNode,
+			"memory_overallocate": input.NumberNode,
+			"disk":                input.NumberNode,
+			"disk_overallocate":   input.NumberNode,
+			"upload_size":         input.NumberNode,
+			"daemon_listen":       input.NumberNode,
+			"daemon_sftp":         input.NumberNode,
+			"daemon_base":         input.StringNode,
+		}, &fields)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		node, err = app.UpdateNode(cmd.Context(), id, fields)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
 
-	if value, _ := flags.GetString("name"); value != "" {
-		params = append(params, "filter[name]="+value)
-	}
+		buf, err := http.HandleItem("node", node, cfg)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
 
-	if value, _ := flags.GetString("uuid"); value != "" {
-		params = append(params, "filter[uuid]="+value)
-	}
+		log.LineB(buf)
+	},
+}
 
-	if value, _ := flags.GetString("fqdn"); value != "" {
-		params = append(params, "filter[fqdn]="+value)
-	}
+var setNodeMaintenanceCmd = &cobra.Command{
+	Use:   "nodes:maintenance id on|off",
+	Short: "toggles maintenance mode for a node",
+	Long:  "Enables or disables maintenance mode for a node by its ID.",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"id", "state"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			log.Error("invalid node id '%s'", args[0])
+			return
+		}
 
-	if value, _ := flags.GetString("token"); value != "" {
-		params = append(params, "filter[daemon_token_id]="+value)
-	}
+		var state bool
 
-	if len(params) > 0 {
-		query.WriteString("?" + params[0])
+		switch args[1] {
+		case "on":
+			state = true
+		case "off":
+			state = false
+		default:
+			log.Error("invalid maintenance state; must be on or off")
+			return
+		}
 
-		if len(params) > 1 {
-			for _, p := range params {
-				query.WriteString("&" + p)
-			}
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		app := ptero.NewApplication(http.New(cfg, &cfg.Application, log))
+		node, err := app.GetNode(cmd.Context(), id)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		if node.MaintenanceMode == state {
+			log.Ignore().Info("maintenance mode is already %s", args[1])
+			return
 		}
-	}
 
-	return single, query.String(), nil
+		fields := node.Descriptor()
+		fields.MaintenanceMode = state
+		if _, err = app.UpdateNode(cmd.Context(), id, fields); err != nil {
+			http.HandleError(err, cfg, log)
+		}
+	},
 }
 
 var getNodeConfigCmd = &cobra.Command{
@@ -109,36 +273,32 @@ var getNodeConfigCmd = &cobra.Command{
 			return
 		}
 
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			log.Error("invalid node id '%s'", args[0])
+			return
+		}
+
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		ctx := http.New(cfg, &cfg.Application, log)
-		req := ctx.Request("GET", "/api/application/nodes/"+args[0]+"/configuration", nil)
-		res, err := ctx.Execute(req)
+		app := ptero.NewApplication(http.New(cfg, &cfg.Application, log))
+		model, err := app.GetNodeConfiguration(cmd.Context(), id)
 		if err != nil {
-			log.WithError(err)
+			http.HandleError(err, cfg, log)
 			return
 		}
 
-		var buf []byte
-
-		if cfg.Http.ParseIndent {
-			var model interface{}
-			if err = json.Unmarshal(res, &model); err != nil {
-				log.WithError(err)
-				return
-			}
-
-			buf, err = json.MarshalIndent(model, "", "  ")
-			if err != nil {
-				log.WithError(err)
-				return
-			}
+		buf, err := http.HandleJSON(model, cfg)
+		if err != nil {
+			log.WithError(err)
+			return
 		}
 
 		log.LineB(buf)
@@ -156,23 +316,35 @@ var getNodeAllocationsCmd = &cobra.Command{
 			return
 		}
 
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			log.Error("invalid node id '%s'", args[0])
+			return
+		}
+
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		ctx := http.New(cfg, &cfg.Application, log)
-		req := ctx.Request("GET", "/api/application/nodes/"+args[0]+"/allocations", nil)
-		res, err := ctx.Execute(req)
+		app := ptero.NewApplication(http.New(cfg, &cfg.Application, log))
+		allocations, meta, err := app.ListNodeAllocations(cmd.Context(), id, util.ParseListOptions(cmd.Flags()))
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		buf, err := http.HandleList("allocation", allocations, meta, cfg)
 		if err != nil {
 			log.WithError(err)
 			return
 		}
 
-		log.LineB(res)
+		log.LineB(buf)
 	},
 }
 
@@ -186,80 +358,41 @@ var createAllocationsCmd = &cobra.Command{
 			return
 		}
 
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			log.Error("invalid node id '%s'", args[0])
+			return
+		}
+
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		var payload []byte
-		data, _ := cmd.Flags().GetString("data")
-		file, _ := cmd.Flags().GetString("file")
-		js, _ := cmd.Flags().GetString("json")
-
-		switch {
-		case data != "":
-			m, err := input.Parse(data)
-			if err != nil {
-				log.WithError(err).Error("failed to parse data input")
-				return
-			}
-
-			payload, err = input.Marshal(input.Definition{
-				"ip":    input.StringNode,
-				"alias": input.NullStringNode,
-				"ports": input.ArrayStringNode,
-			}, m)
-			if err != nil {
-				log.WithError(err).Error("failed to parse data input")
-				return
-			}
-		case file != "":
-			v, err := util.SafeReadFile(file)
-			if err != nil {
-				log.WithError(err)
-				return
-			}
-
-			payload, err = util.ValidateSchema(v, struct {
-				IP    string   `json:"ip"`
-				Alias string   `json:"alias,omitempty"`
-				Ports []string `json:"ports"`
-			}{})
-			if err != nil {
-				log.WithError(err).Error("failed to parse json input")
-				return
-			}
-		case js != "":
-			payload, err = util.ValidateSchema([]byte(js), struct {
-				IP    string   `json:"ip"`
-				Alias string   `json:"alias,omitempty"`
-				Ports []string `json:"ports"`
-			}{})
-			if err != nil {
-				log.WithError(err).Error("failed to parse json input")
-				return
-			}
-		default:
-			log.Error("no data source provided").Error("'--data', '--data-file' or '--data-json' must be specified")
+		var fields ptero.CreateAllocationsDescriptor
+		err = util.ReadDataFlags(cmd.Flags(), input.Definition{
+			"ip":    input.StringNode,
+			"alias": input.NullStringNode,
+			"ports": input.ArrayStringNode,
+		}, &fields)
+		if err != nil {
+			log.WithError(err)
 			return
 		}
 
-		body := bytes.Buffer{}
-		body.Write(payload)
-
-		ctx := http.New(cfg, &cfg.Application, log)
-		req := ctx.Request("POST", fmt.Sprintf("/api/application/nodes/%s/allocations", args[0]), &body)
-		if _, err = ctx.Execute(req); err != nil {
-			log.WithError(err)
+		app := ptero.NewApplication(http.New(cfg, &cfg.Application, log))
+		if err = app.CreateNodeAllocations(cmd.Context(), id, fields); err != nil {
+			http.HandleError(err, cfg, log)
 		}
 	},
 }
 
 var deleteAllocationCmd = &cobra.Command{
-	Use:   "nodes:alloc:delete node id",
+	Use:   "nodes:alloc:delete node id [-y]",
 	Short: "deletes an allocation",
 	Long:  "Deletes an allocation from a specified node.",
 	Run: func(cmd *cobra.Command, args []string) {
@@ -269,18 +402,53 @@ var deleteAllocationCmd = &cobra.Command{
 			return
 		}
 
+		node, err := strconv.Atoi(args[0])
+		if err != nil {
+			log.Error("invalid node id '%s'", args[0])
+			return
+		}
+
+		id, err := strconv.Atoi(args[1])
+		if err != nil {
+			log.Error("invalid allocation id '%s'", args[1])
+			return
+		}
+
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		ctx := http.New(cfg, &cfg.Application, log)
-		req := ctx.Request("DELETE", fmt.Sprintf("/api/application/nodes/%s/allocations/%s", args[0], args[1]), nil)
-		if _, err = ctx.Execute(req); err != nil {
+		app := ptero.NewApplication(http.New(cfg, &cfg.Application, log))
+		allocations, _, err := app.ListNodeAllocations(cmd.Context(), node, &ptero.ListOptions{All: true})
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		var address string
+		for _, a := range allocations {
+			if a.ID == id {
+				address = fmt.Sprintf("%s:%d", a.IP, a.Port)
+			}
+		}
+		if address == "" {
+			log.Error("allocation %d not found on node %d", id, node)
+			return
+		}
+
+		prompt := fmt.Sprintf("delete allocation %s from node %d?", address, node)
+		if err = util.Confirm(cmd.Flags(), prompt, address); err != nil {
 			log.WithError(err)
+			os.Exit(1)
+		}
+
+		if err = app.DeleteNodeAllocation(cmd.Context(), node, id); err != nil {
+			http.HandleError(err, cfg, log)
 		}
 	},
 }
@@ -1,12 +1,16 @@
 package app
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"strings"
+	"os"
+	"strconv"
 
 	"github.com/pteropackages/soar/config"
 	"github.com/pteropackages/soar/http"
+	"github.com/pteropackages/soar/input"
+	"github.com/pteropackages/soar/ptero"
 	"github.com/pteropackages/soar/util"
 	"github.com/spf13/cobra"
 )
@@ -19,34 +23,172 @@ var getServersCmd = &cobra.Command{
 		log.ApplyFlags(cmd.Flags())
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		single, query, err := parseServerQuery(cmd)
+		id, _ := cmd.Flags().GetInt("id")
+		ext, _ := cmd.Flags().GetString("external")
+		if id != 0 && ext != "" {
+			log.Error("command error:").WithError(errors.New("id and external flags specified; pick one"))
+			return
+		}
+
+		app := ptero.NewApplication(http.New(cfg, &cfg.Application, log))
+		var buf []byte
+
+		if id != 0 || ext != "" {
+			var server *ptero.Server
+			if id != 0 {
+				server, err = app.GetServer(cmd.Context(), id)
+			} else {
+				server, err = app.GetServerExternal(cmd.Context(), ext)
+			}
+			if err != nil {
+				http.HandleError(err, cfg, log)
+				return
+			}
+
+			buf, err = http.HandleItem("server", server, cfg)
+		} else {
+			opts := util.ParseListOptions(cmd.Flags())
+			filters := map[string]string{
+				"name":  "name",
+				"desc":  "description",
+				"uuid":  "uuid",
+				"image": "image",
+			}
+			for flag, key := range filters {
+				if val, _ := cmd.Flags().GetString(flag); val != "" {
+					opts.Filters[key] = val
+				}
+			}
+
+			var servers []*ptero.Server
+			var meta *ptero.Meta
+			servers, meta, err = app.ListServers(cmd.Context(), opts)
+			if err != nil {
+				http.HandleError(err, cfg, log)
+				return
+			}
+
+			buf, err = http.HandleList("server", servers, meta, cfg)
+		}
 		if err != nil {
-			log.Error("command error:").WithError(err)
+			log.WithError(err)
 			return
 		}
 
-		ctx := http.New(cfg, &cfg.Application, log)
-		req := ctx.Request("GET", "/api/application/servers"+query, nil)
-		res, err := ctx.ExecuteWithFlags(req, cmd.Flags())
+		log.LineB(buf)
+	},
+}
+
+var createServerCmd = &cobra.Command{
+	Use:   "servers:create --data[-file | -json] source [--env key=value]",
+	Short: "creates a server",
+	Long:  createServerHelp,
+	Run: func(cmd *cobra.Command, _ []string) {
+		log.ApplyFlags(cmd.Flags())
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		var fields struct {
+			ptero.CreateServerDescriptor
+			Nest int `json:"nest"`
+		}
+		fields.Limits.IO = 500
+
+		err = util.ReadPartialDataFlags(cmd.Flags(), input.Definition{
+			"name":                       input.StringNode,
+			"description":                input.StringNode,
+			"external_id":                input.StringNode,
+			"user":                       input.NumberNode,
+			"nest":                       input.NumberNode,
+			"egg":                        input.NumberNode,
+			"docker_image":               input.StringNode,
+			"startup":                    input.StringNode,
+			"limits.memory":              input.NumberNode,
+			"limits.swap":                input.NumberNode,
+			"limits.disk":                input.NumberNode,
+			"limits.io":                  input.NumberNode,
+			"limits.cpu":                 input.NumberNode,
+			"limits.threads":             input.StringNode,
+			"limits.oom_disabled":        input.BoolNode,
+			"feature_limits.databases":   input.NumberNode,
+			"feature_limits.allocations": input.NumberNode,
+			"feature_limits.backups":     input.NumberNode,
+			"allocation.default":         input.NumberNode,
+			"allocation.additional":      input.ArrayNumberNode,
+			"deploy.locations":           input.ArrayNumberNode,
+			"deploy.dedicated_ip":        input.BoolNode,
+			"deploy.port_range":          input.ArrayStringNode,
+			"skip_scripts":               input.BoolNode,
+			"start_on_completion":        input.BoolNode,
+		}, &fields)
 		if err != nil {
 			log.WithError(err)
 			return
 		}
 
-		var buf []byte
+		env, _ := cmd.Flags().GetStringArray("env")
+		vars, err := util.ParseKeyValues(env)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+		if fields.Environment == nil {
+			fields.Environment = map[string]interface{}{}
+		}
+		for k, v := range vars {
+			fields.Environment[k] = v
+		}
 
-		if single {
-			buf, err = http.HandleItemResponse(res, cfg)
-		} else {
-			buf, err = http.HandleDataResponse(res, cfg)
+		if fields.Name == "" || fields.User == 0 || fields.Nest == 0 || fields.Egg == 0 {
+			log.Error("the name, user, nest and egg fields are required")
+			return
+		}
+
+		hasAllocation := fields.Allocation != nil && fields.Allocation.Default != 0
+		hasDeploy := fields.Deploy != nil && len(fields.Deploy.Locations) != 0
+		if hasAllocation == hasDeploy {
+			log.Error("either allocation.default or deploy.locations must be specified (but not both)")
+			return
+		}
+		if !hasAllocation {
+			fields.Allocation = nil
+		}
+		if hasDeploy && fields.Deploy.PortRange == nil {
+			fields.Deploy.PortRange = []string{}
 		}
+		if !hasDeploy {
+			fields.Deploy = nil
+		}
+
+		app := ptero.NewApplication(http.New(cfg, &cfg.Application, log))
+		if err = app.ApplyEggDefaults(cmd.Context(), fields.Nest, &fields.CreateServerDescriptor); err != nil {
+			log.Error("failed to get egg defaults:")
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		server, err := app.CreateServer(cmd.Context(), fields.CreateServerDescriptor)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		buf, err := http.HandleItem("server", server, cfg)
 		if err != nil {
 			log.WithError(err)
 			return
@@ -56,59 +198,141 @@ var getServersCmd = &cobra.Command{
 	},
 }
 
-func parseServerQuery(cmd *cobra.Command) (bool, string, error) {
-	var query strings.Builder
-	var params []string
-	single := false
-	flags := cmd.Flags()
+var updateServerBuildCmd = &cobra.Command{
+	Use:   "servers:update:build id --data[-file | -json] source",
+	Short: "updates the build configuration of a server",
+	Long:  updateServerBuildHelp,
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"id"}); err != nil {
+			log.WithError(err)
+			return
+		}
 
-	if id, _ := flags.GetInt("id"); id != 0 {
-		single = true
-		query.WriteString(fmt.Sprintf("/%d", id))
-	}
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			log.Error("invalid server id '%s'", args[0])
+			return
+		}
 
-	if ext, _ := flags.GetString("external"); ext != "" {
-		if query.Len() != 0 {
-			return false, "", errors.New("id and external flags specified; pick one")
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
 		}
+		cfg.ApplyFlags(cmd.Flags())
 
-		single = true
-		query.WriteString("/external/" + ext)
-	}
+		app := ptero.NewApplication(http.New(cfg, &cfg.Application, log))
+		server, err := app.GetServer(cmd.Context(), id)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		fields := server.BuildDescriptor()
+		err = util.ReadPartialDataFlags(cmd.Flags(), input.Definition{
+			"allocation":                 input.NumberNode,
+			"limits.memory":              input.NumberNode,
+			"limits.swap":                input.NumberNode,
+			"limits.disk":                input.NumberNode,
+			"limits.io":                  input.NumberNode,
+			"limits.cpu":                 input.NumberNode,
+			"limits.threads":             input.NullStringNode,
+			"limits.oom_disabled":        input.BoolNode,
+			"feature_limits.databases":   input.NumberNode,
+			"feature_limits.allocations": input.NumberNode,
+			"feature_limits.backups":     input.NumberNode,
+			"add_allocations":            input.ArrayNumberNode,
+			"remove_allocations":         input.ArrayNumberNode,
+		}, &fields)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		server, err = app.UpdateServerBuild(cmd.Context(), id, fields)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		buf, err := http.HandleItem("server", server, cfg)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		log.LineB(buf)
+	},
+}
 
-	if value, _ := flags.GetString("name"); value != "" {
-		params = append(params, "filter[name]="+value)
-	}
+var updateServerDetailsCmd = &cobra.Command{
+	Use:   "servers:update:details id --data[-file | -json] source",
+	Short: "updates the details information of a server",
+	Long:  updateServerDetailsHelp,
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"id"}); err != nil {
+			log.WithError(err)
+			return
+		}
 
-	if value, _ := flags.GetString("desc"); value != "" {
-		params = append(params, "filter[description]="+value)
-	}
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			log.Error("invalid server id '%s'", args[0])
+			return
+		}
 
-	if value, _ := flags.GetString("uuid"); value != "" {
-		params = append(params, "filter[uuid]="+value)
-	}
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
 
-	if value, _ := flags.GetString("image"); value != "" {
-		params = append(params, "filter[image]="+value)
-	}
+		app := ptero.NewApplication(http.New(cfg, &cfg.Application, log))
+		server, err := app.GetServer(cmd.Context(), id)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
 
-	if len(params) > 0 {
-		query.WriteString("?" + params[0])
+		fields := server.DetailsDescriptor()
+		err = util.ReadPartialDataFlags(cmd.Flags(), input.Definition{
+			"name":        input.StringNode,
+			"user":        input.NumberNode,
+			"external_id": input.NullStringNode,
+			"description": input.StringNode,
+		}, &fields)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
 
-		if len(params) > 1 {
-			for _, p := range params {
-				query.WriteString("&" + p)
-			}
+		server, err = app.UpdateServerDetails(cmd.Context(), id, fields)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
 		}
-	}
 
-	return single, query.String(), nil
+		buf, err := http.HandleItem("server", server, cfg)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		log.LineB(buf)
+	},
 }
 
-var suspendServerCmd = &cobra.Command{
-	Use:   "servers:suspend id",
-	Short: "suspends a server",
-	Long:  "Suspends a server on the panel by its ID.",
+var updateServerStartupCmd = &cobra.Command{
+	Use:   "servers:update:startup id --data[-file | -json] source [--env key=value]",
+	Short: "updates the startup configuration of a server",
+	Long:  updateServerStartupHelp,
 	Run: func(cmd *cobra.Command, args []string) {
 		log.ApplyFlags(cmd.Flags())
 		if err := util.RequireArgs(args, []string{"id"}); err != nil {
@@ -116,104 +340,337 @@ var suspendServerCmd = &cobra.Command{
 			return
 		}
 
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			log.Error("invalid server id '%s'", args[0])
+			return
+		}
+
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		ctx := http.New(cfg, &cfg.Application, log)
-		req := ctx.Request("POST", "/api/application/servers/"+args[0]+"/suspend", nil)
-		if _, err = ctx.Execute(req); err != nil {
+		app := ptero.NewApplication(http.New(cfg, &cfg.Application, log))
+		server, err := app.GetServer(cmd.Context(), id)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		fields := server.StartupDescriptor()
+		err = util.ReadPartialDataFlags(cmd.Flags(), input.Definition{
+			"startup":      input.StringNode,
+			"egg":          input.NumberNode,
+			"image":        input.StringNode,
+			"skip_scripts": input.BoolNode,
+		}, &fields)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		env, _ := cmd.Flags().GetStringArray("env")
+		vars, err := util.ParseKeyValues(env)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+		for k, v := range vars {
+			fields.Environment[k] = v
+		}
+
+		server, err = app.UpdateServerStartup(cmd.Context(), id, fields)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		buf, err := http.HandleItem("server", server, cfg)
+		if err != nil {
 			log.WithError(err)
+			return
+		}
+
+		log.LineB(buf)
+	},
+}
+
+var suspendServerCmd = &cobra.Command{
+	Use:   "servers:suspend id [--wait] [--timeout duration]\n\t[--selector key=value,...] [--from-file path] [--parallel n]",
+	Short: "suspends a server",
+	Long:  "Suspends a server on the panel by its ID.\n\n" + bulkHelp,
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		bulk := util.IsBulk(cmd.Flags(), args)
+		var id int
+		if !bulk {
+			if err := util.RequireArgs(args, []string{"id"}); err != nil {
+				log.WithError(err)
+				return
+			}
+
+			var err error
+			if id, err = strconv.Atoi(args[0]); err != nil {
+				log.Error("invalid server id '%s'", args[0])
+				return
+			}
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		app := ptero.NewApplication(http.New(cfg, &cfg.Application, log))
+		if bulk {
+			ids, err := serverTargets(cmd, app, args)
+			if err != nil {
+				http.HandleError(err, cfg, log)
+				return
+			}
+
+			wait, _ := cmd.Flags().GetBool("wait")
+			timeout, _ := cmd.Flags().GetDuration("timeout")
+			runBulk(cmd, "server", ids, func(ctx context.Context, id int) error {
+				if err := app.SuspendServer(ctx, id); err != nil || !wait {
+					return err
+				}
+
+				ctx, cancel := context.WithTimeout(ctx, timeout)
+				defer cancel()
+				return app.WaitForSuspension(ctx, id)
+			})
+			return
+		}
+
+		if err = app.SuspendServer(cmd.Context(), id); err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		if wait, _ := cmd.Flags().GetBool("wait"); wait {
+			timeout, _ := cmd.Flags().GetDuration("timeout")
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			log.Ignore().Info("waiting for the server to be suspended")
+			if err = app.WaitForSuspension(ctx, id); err != nil {
+				http.HandleError(err, cfg, log)
+				os.Exit(1)
+			}
 		}
 	},
 }
 
 var unsuspendServerCmd = &cobra.Command{
-	Use:   "servers:unsuspend id",
+	Use:   "servers:unsuspend id [--selector key=value,...] [--from-file path] [--parallel n]",
 	Short: "unsuspends a server",
-	Long:  "Unsuspends a server on the panel by its ID.",
+	Long:  "Unsuspends a server on the panel by its ID.\n\n" + bulkHelp,
 	Run: func(cmd *cobra.Command, args []string) {
 		log.ApplyFlags(cmd.Flags())
-		if err := util.RequireArgs(args, []string{"id"}); err != nil {
-			log.WithError(err)
-			return
+		bulk := util.IsBulk(cmd.Flags(), args)
+		var id int
+		if !bulk {
+			if err := util.RequireArgs(args, []string{"id"}); err != nil {
+				log.WithError(err)
+				return
+			}
+
+			var err error
+			if id, err = strconv.Atoi(args[0]); err != nil {
+				log.Error("invalid server id '%s'", args[0])
+				return
+			}
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		ctx := http.New(cfg, &cfg.Application, log)
-		req := ctx.Request("POST", "/api/application/servers/"+args[0]+"/unsuspend", nil)
-		if _, err = ctx.Execute(req); err != nil {
-			log.WithError(err)
+		app := ptero.NewApplication(http.New(cfg, &cfg.Application, log))
+		if bulk {
+			ids, err := serverTargets(cmd, app, args)
+			if err != nil {
+				http.HandleError(err, cfg, log)
+				return
+			}
+
+			runBulk(cmd, "server", ids, app.UnsuspendServer)
+			return
+		}
+
+		if err = app.UnsuspendServer(cmd.Context(), id); err != nil {
+			http.HandleError(err, cfg, log)
 		}
 	},
 }
 
 var reinstallServerCmd = &cobra.Command{
-	Use:   "servers:reinstall id",
+	Use:   "servers:reinstall id [-y] [--wait] [--timeout duration]\n\t[--selector key=value,...] [--from-file path] [--parallel n]",
 	Short: "reinstalls a server",
-	Long:  "Triggers the reinstall process for a server by its ID.",
+	Long:  "Triggers the reinstall process for a server by its ID.\n\n" + bulkHelp,
 	Run: func(cmd *cobra.Command, args []string) {
 		log.ApplyFlags(cmd.Flags())
-		if err := util.RequireArgs(args, []string{"id"}); err != nil {
-			log.WithError(err)
-			return
+		bulk := util.IsBulk(cmd.Flags(), args)
+		var id int
+		if !bulk {
+			if err := util.RequireArgs(args, []string{"id"}); err != nil {
+				log.WithError(err)
+				return
+			}
+
+			var err error
+			if id, err = strconv.Atoi(args[0]); err != nil {
+				log.Error("invalid server id '%s'", args[0])
+				return
+			}
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		ctx := http.New(cfg, &cfg.Application, log)
-		req := ctx.Request("POST", "/api/application/servers/"+args[0]+"/reinstall", nil)
-		if _, err = ctx.Execute(req); err != nil {
+		app := ptero.NewApplication(http.New(cfg, &cfg.Application, log))
+		if bulk {
+			ids, err := serverTargets(cmd, app, args)
+			if err != nil {
+				http.HandleError(err, cfg, log)
+				return
+			}
+
+			if err = confirmBulk(cmd, "reinstall", "server", ids); err != nil {
+				log.WithError(err)
+				os.Exit(1)
+			}
+
+			wait, _ := cmd.Flags().GetBool("wait")
+			timeout, _ := cmd.Flags().GetDuration("timeout")
+			runBulk(cmd, "server", ids, func(ctx context.Context, id int) error {
+				if err := app.ReinstallServer(ctx, id); err != nil || !wait {
+					return err
+				}
+
+				ctx, cancel := context.WithTimeout(ctx, timeout)
+				defer cancel()
+				return app.WaitForInstall(ctx, id)
+			})
+			return
+		}
+
+		server, err := app.GetServer(cmd.Context(), id)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		prompt := fmt.Sprintf("reinstall server '%s' (uuid %s)? this will run the install script again", server.Name, server.UUID)
+		if err = util.Confirm(cmd.Flags(), prompt, server.Name); err != nil {
 			log.WithError(err)
+			os.Exit(1)
+		}
+
+		if err = app.ReinstallServer(cmd.Context(), id); err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		if wait, _ := cmd.Flags().GetBool("wait"); wait {
+			timeout, _ := cmd.Flags().GetDuration("timeout")
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			log.Ignore().Info("waiting for the server to be installed")
+			if err = app.WaitForInstall(ctx, id); err != nil {
+				http.HandleError(err, cfg, log)
+				os.Exit(1)
+			}
 		}
 	},
 }
 
 var deleteServerCmd = &cobra.Command{
-	Use:   "servers:delete id [--force]",
+	Use:   "servers:delete id [-y] [--force] [--selector key=value,...] [--from-file path] [--parallel n]",
 	Short: "deletes a server",
-	Long:  "Deletes a server on the panel by its ID (supports the --force flag).",
+	Long:  "Deletes a server on the panel by its ID (supports the --force flag).\n\n" + bulkHelp,
 	Run: func(cmd *cobra.Command, args []string) {
 		log.ApplyFlags(cmd.Flags())
-		if err := util.RequireArgs(args, []string{"id"}); err != nil {
-			log.WithError(err)
-			return
+		bulk := util.IsBulk(cmd.Flags(), args)
+		var id int
+		if !bulk {
+			if err := util.RequireArgs(args, []string{"id"}); err != nil {
+				log.WithError(err)
+				return
+			}
+
+			var err error
+			if id, err = strconv.Atoi(args[0]); err != nil {
+				log.Error("invalid server id '%s'", args[0])
+				return
+			}
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		path := "/api/application/servers/" + args[0]
-		if force, _ := cmd.Flags().GetBool("force"); force {
-			path += "/force"
+		force, _ := cmd.Flags().GetBool("force")
+		app := ptero.NewApplication(http.New(cfg, &cfg.Application, log))
+		if bulk {
+			ids, err := serverTargets(cmd, app, args)
+			if err != nil {
+				http.HandleError(err, cfg, log)
+				return
+			}
+
+			if err = confirmBulk(cmd, "delete", "server", ids); err != nil {
+				log.WithError(err)
+				os.Exit(1)
+			}
+
+			runBulk(cmd, "server", ids, func(ctx context.Context, id int) error {
+				return app.DeleteServer(ctx, id, force)
+			})
+			return
 		}
 
-		ctx := http.New(cfg, &cfg.Application, log)
-		req := ctx.Request("DELETE", path, nil)
-		if _, err = ctx.Execute(req); err != nil {
+		server, err := app.GetServer(cmd.Context(), id)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		prompt := fmt.Sprintf("delete server '%s' (uuid %s)?", server.Name, server.UUID)
+		if err = util.Confirm(cmd.Flags(), prompt, server.Name); err != nil {
 			log.WithError(err)
+			os.Exit(1)
+		}
+
+		if err = app.DeleteServer(cmd.Context(), id, force); err != nil {
+			http.HandleError(err, cfg, log)
 		}
 	},
 }
@@ -1,14 +1,15 @@
 package app
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
-	"strings"
+	"os"
+	"strconv"
 
 	"github.com/pteropackages/soar/config"
 	"github.com/pteropackages/soar/http"
 	"github.com/pteropackages/soar/input"
+	"github.com/pteropackages/soar/ptero"
 	"github.com/pteropackages/soar/util"
 	"github.com/spf13/cobra"
 )
@@ -21,33 +22,54 @@ var getUsersCmd = &cobra.Command{
 		log.ApplyFlags(cmd.Flags())
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		single, query, err := parseUserQuery(cmd)
-		if err != nil {
-			log.Error("command error:").WithError(err)
-			return
-		}
-
-		ctx := http.New(cfg, &cfg.Application, log)
-		req := ctx.Request("GET", "/api/application/users"+query, nil)
-		res, err := ctx.ExecuteWithFlags(req, cmd.Flags())
-		if err != nil {
-			log.WithError(err)
+		id, _ := cmd.Flags().GetInt("id")
+		ext, _ := cmd.Flags().GetString("external")
+		if id != 0 && ext != "" {
+			log.Error("command error:").WithError(errors.New("id and external flags specified; pick one"))
 			return
 		}
 
+		app := ptero.NewApplication(http.New(cfg, &cfg.Application, log))
 		var buf []byte
 
-		if single {
-			buf, err = http.HandleItemResponse(res, cfg)
+		if id != 0 || ext != "" {
+			var user *ptero.User
+			if id != 0 {
+				user, err = app.GetUser(cmd.Context(), id)
+			} else {
+				user, err = app.GetUserExternal(cmd.Context(), ext)
+			}
+			if err != nil {
+				http.HandleError(err, cfg, log)
+				return
+			}
+
+			buf, err = http.HandleItem("user", user, cfg)
 		} else {
-			buf, err = http.HandleDataResponse(res, cfg)
+			opts := util.ParseListOptions(cmd.Flags())
+			for _, key := range []string{"username", "email", "uuid"} {
+				if val, _ := cmd.Flags().GetString(key); val != "" {
+					opts.Filters[key] = val
+				}
+			}
+
+			var users []*ptero.User
+			var meta *ptero.Meta
+			users, meta, err = app.ListUsers(cmd.Context(), opts)
+			if err != nil {
+				http.HandleError(err, cfg, log)
+				return
+			}
+
+			buf, err = http.HandleList("user", users, meta, cfg)
 		}
 		if err != nil {
 			log.WithError(err)
@@ -58,51 +80,6 @@ var getUsersCmd = &cobra.Command{
 	},
 }
 
-func parseUserQuery(cmd *cobra.Command) (bool, string, error) {
-	var query strings.Builder
-	var params []string
-	single := false
-	flags := cmd.Flags()
-
-	if id, _ := flags.GetInt("id"); id != 0 {
-		single = true
-		query.WriteString(fmt.Sprintf("/%d", id))
-	}
-
-	if ext, _ := flags.GetString("external"); ext != "" {
-		if query.Len() != 0 {
-			return false, "", errors.New("id and external flags specified; pick one")
-		}
-
-		single = true
-		query.WriteString("/external/" + ext)
-	}
-
-	if val, _ := flags.GetString("username"); val != "" {
-		params = append(params, "filter[username]="+val)
-	}
-
-	if val, _ := flags.GetString("email"); val != "" {
-		params = append(params, "filter[email]="+val)
-	}
-
-	if val, _ := flags.GetString("uuid"); val != "" {
-		params = append(params, "filter[uuid]="+val)
-	}
-
-	if len(params) > 0 {
-		query.WriteString("?" + params[0])
-
-		if len(params) > 1 {
-			for _, p := range params {
-				query.WriteString("&" + p)
-			}
-		}
-	}
-
-	return single, query.String(), nil
-}
-
 var createUserCmd = &cobra.Command{
 	Use:   "users:create --data[-file | -json] source",
 	Short: "creates a user",
@@ -111,90 +88,102 @@ var createUserCmd = &cobra.Command{
 		log.ApplyFlags(cmd.Flags())
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		var payload []byte
-		data, _ := cmd.Flags().GetString("data")
-		file, _ := cmd.Flags().GetString("file")
-		js, _ := cmd.Flags().GetString("json")
+		var fields ptero.CreateUserDescriptor
+		err = util.ReadDataFlags(cmd.Flags(), input.Definition{
+			"username":    input.StringNode,
+			"email":       input.StringNode,
+			"external_id": input.NullStringNode,
+			"first_name":  input.StringNode,
+			"last_name":   input.StringNode,
+			"root_admin":  input.BoolNode,
+			"password":    input.NullStringNode,
+		}, &fields)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
 
-		switch {
-		case data != "":
-			m, err := input.Parse(data)
-			if err != nil {
-				log.WithError(err).Error("failed to parse data input")
-				return
-			}
+		app := ptero.NewApplication(http.New(cfg, &cfg.Application, log))
+		user, err := app.CreateUser(cmd.Context(), fields)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
 
-			payload, err = input.Marshal(input.Definition{
-				"username":    input.StringNode,
-				"email":       input.StringNode,
-				"external_id": input.NullStringNode,
-				"first_name":  input.StringNode,
-				"last_name":   input.StringNode,
-				"root_admin":  input.BoolNode,
-				"password":    input.NullStringNode,
-			}, m)
-			if err != nil {
-				log.WithError(err).Error("failed to parse data input")
-				return
-			}
-		case file != "":
-			v, err := util.SafeReadFile(file)
-			if err != nil {
-				log.WithError(err)
-				return
-			}
+		buf, err := http.HandleItem("user", user, cfg)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
 
-			payload, err = util.ValidateSchema(v, struct {
-				Username   string `json:"username"`
-				Email      string `json:"email"`
-				ExternalID string `json:"external_id,omitempty"`
-				FirstName  string `json:"first_name"`
-				LastName   string `json:"last_name"`
-				RootAdmin  bool   `json:"root_admin,omitempty"`
-				Password   string `json:"password,omitempty"`
-			}{})
-			if err != nil {
-				log.WithError(err).Error("failed to parse json input")
-				return
-			}
-		case js != "":
-			payload, err = util.ValidateSchema([]byte(js), struct {
-				Username   string `json:"username"`
-				Email      string `json:"email"`
-				ExternalID string `json:"external_id,omitempty"`
-				FirstName  string `json:"first_name"`
-				LastName   string `json:"last_name"`
-				RootAdmin  bool   `json:"root_admin,omitempty"`
-				Password   string `json:"password,omitempty"`
-			}{})
-			if err != nil {
-				log.WithError(err).Error("failed to parse json input")
-				return
-			}
-		default:
-			log.Error("no data source provided").Error("'--data', '--data-file' or '--data-json' must be specified")
+		log.LineB(buf)
+	},
+}
+
+var updateUserCmd = &cobra.Command{
+	Use:   "users:update id --data[-file | -json] source",
+	Short: "updates a user",
+	Long:  updateUserHelp,
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"id"}); err != nil {
+			log.WithError(err)
 			return
 		}
 
-		body := bytes.Buffer{}
-		body.Write(payload)
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			log.Error("invalid user id '%s'", args[0])
+			return
+		}
 
-		ctx := http.New(cfg, &cfg.Application, log)
-		req := ctx.Request("POST", "/api/application/users", &body)
-		res, err := ctx.Execute(req)
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		app := ptero.NewApplication(http.New(cfg, &cfg.Application, log))
+		user, err := app.GetUser(cmd.Context(), id)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		fields := user.UpdateDescriptor()
+		err = util.ReadPartialDataFlags(cmd.Flags(), input.Definition{
+			"username":    input.StringNode,
+			"email":       input.StringNode,
+			"external_id": input.NullStringNode,
+			"first_name":  input.StringNode,
+			"last_name":   input.StringNode,
+			"language":    input.StringNode,
+			"root_admin":  input.BoolNode,
+			"password":    input.StringNode,
+		}, &fields)
 		if err != nil {
 			log.WithError(err)
 			return
 		}
 
-		buf, err := http.HandleItemResponse(res, cfg)
+		user, err = app.UpdateUser(cmd.Context(), id, fields)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		buf, err := http.HandleItem("user", user, cfg)
 		if err != nil {
 			log.WithError(err)
 			return
@@ -205,28 +194,66 @@ var createUserCmd = &cobra.Command{
 }
 
 var deleteUserCmd = &cobra.Command{
-	Use:   "users:delete id",
+	Use:   "users:delete id [-y] [--selector key=value,...] [--from-file path] [--parallel n]",
 	Short: "deletes a user",
-	Long:  "Deletes a user account from the panel by its ID.",
+	Long:  "Deletes a user account from the panel by its ID.\n\n" + bulkHelp,
 	Run: func(cmd *cobra.Command, args []string) {
 		log.ApplyFlags(cmd.Flags())
-		if err := util.RequireArgs(args, []string{"id"}); err != nil {
-			log.WithError(err)
-			return
+		bulk := util.IsBulk(cmd.Flags(), args)
+		var id int
+		if !bulk {
+			if err := util.RequireArgs(args, []string{"id"}); err != nil {
+				log.WithError(err)
+				return
+			}
+
+			var err error
+			if id, err = strconv.Atoi(args[0]); err != nil {
+				log.Error("invalid user id '%s'", args[0])
+				return
+			}
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		ctx := http.New(cfg, &cfg.Application, log)
-		req := ctx.Request("DELETE", "/api/application/users/"+args[0], nil)
-		if _, err := ctx.Execute(req); err != nil {
+		app := ptero.NewApplication(http.New(cfg, &cfg.Application, log))
+		if bulk {
+			ids, err := userTargets(cmd, app, args)
+			if err != nil {
+				http.HandleError(err, cfg, log)
+				return
+			}
+
+			if err = confirmBulk(cmd, "delete", "user", ids); err != nil {
+				log.WithError(err)
+				os.Exit(1)
+			}
+
+			runBulk(cmd, "user", ids, app.DeleteUser)
+			return
+		}
+
+		user, err := app.GetUser(cmd.Context(), id)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		prompt := fmt.Sprintf("delete user '%s' (%s)?", user.Username, user.Email)
+		if err = util.Confirm(cmd.Flags(), prompt, user.Username); err != nil {
 			log.WithError(err)
+			os.Exit(1)
+		}
+
+		if err = app.DeleteUser(cmd.Context(), id); err != nil {
+			http.HandleError(err, cfg, log)
 		}
 	},
 }
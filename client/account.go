@@ -1,12 +1,13 @@
 package client
 
 import (
-	"bytes"
-	"encoding/json"
-	"net/url"
+	"fmt"
+	"os"
+	"strings"
 
 	"github.com/pteropackages/soar/config"
 	"github.com/pteropackages/soar/http"
+	"github.com/pteropackages/soar/ptero"
 	"github.com/pteropackages/soar/util"
 	"github.com/spf13/cobra"
 )
@@ -18,22 +19,22 @@ var getAccountCmd = &cobra.Command{
 		log.ApplyFlags(cmd.Flags())
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("GET", "/api/client/account", nil)
-		res, err := ctx.Execute(req)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		account, err := client.GetAccount(cmd.Context())
 		if err != nil {
-			log.WithError(err)
+			http.HandleError(err, cfg, log)
 			return
 		}
 
-		buf, err := http.HandleItemResponse(res, cfg)
+		buf, err := http.HandleItem("user", account, cfg)
 		if err != nil {
 			log.WithError(err)
 			return
@@ -51,25 +52,22 @@ var getPermissionsCmd = &cobra.Command{
 		log.ApplyFlags(cmd.Flags())
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("GET", "/api/client/permissions", nil)
-		res, err := ctx.Execute(req)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		perms, err := client.GetPermissions(cmd.Context())
 		if err != nil {
-			log.WithError(err)
-			return
-		}
-		if res == nil {
+			http.HandleError(err, cfg, log)
 			return
 		}
 
-		buf, err := http.HandleItemResponse(res, cfg)
+		buf, err := http.HandleItem("system_permissions", perms, cfg)
 		if err != nil {
 			log.WithError(err)
 			return
@@ -87,7 +85,8 @@ var getTwoFactorCodeCmd = &cobra.Command{
 		log.ApplyFlags(cmd.Flags())
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
@@ -95,45 +94,19 @@ var getTwoFactorCodeCmd = &cobra.Command{
 		cfg.ApplyFlags(cmd.Flags())
 
 		log.Warn("BUG: image_url_data '&' is escaped")
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("GET", "/api/client/account/two-factor", nil)
-		res, err := ctx.Execute(req)
-		if err != nil {
-			log.WithError(err)
-			return
-		}
-
-		var model struct {
-			Data struct {
-				ImageURLData string `json:"image_url_data"`
-				Secret       string `json:"secret"`
-			} `json:"data"`
-		}
-		if err = json.Unmarshal(res, &model); err != nil {
-			log.WithError(err)
-			return
-		}
-
-		model.Data.ImageURLData, err = url.PathUnescape(model.Data.ImageURLData)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		data, err := client.GetTwoFactor(cmd.Context())
 		if err != nil {
-			log.Error("failed to parse url:").WithError(err)
+			http.HandleError(err, cfg, log)
 			return
 		}
 
 		var buf []byte
 
 		if cfg.Http.ParseBody {
-			if cfg.Http.ParseIndent {
-				buf, err = json.MarshalIndent(model.Data, "", "  ")
-			} else {
-				buf, err = json.Marshal(model.Data)
-			}
+			buf, err = http.HandleJSON(data, cfg)
 		} else {
-			if cfg.Http.ParseIndent {
-				buf, err = json.MarshalIndent(model, "", "  ")
-			} else {
-				buf, err = json.Marshal(model)
-			}
+			buf, err = http.HandleJSON(map[string]interface{}{"data": data}, cfg)
 		}
 		if err != nil {
 			log.WithError(err)
@@ -156,26 +129,22 @@ var enableTwoFactorCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		data, _ := json.Marshal(map[string]string{"code": args[0], "password": args[1]})
-		body := bytes.Buffer{}
-		body.Write(data)
-
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("POST", "/api/client/account/two-factor", &body)
-		res, err := ctx.Execute(req)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		tokens, err := client.EnableTwoFactor(cmd.Context(), args[0], args[1])
 		if err != nil {
-			log.WithError(err)
+			http.HandleError(err, cfg, log)
 			return
 		}
 
-		buf, err := http.HandleDataResponse(res, cfg)
+		buf, err := http.HandleItem("recovery_tokens", tokens, cfg)
 		if err != nil {
 			log.WithError(err)
 			return
@@ -196,22 +165,17 @@ var disableTwoFactorCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		data, _ := json.Marshal(map[string]string{"password": args[0]})
-		body := bytes.Buffer{}
-		body.Write(data)
-
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("DELETE", "/api/client/account/two-factor", &body)
-		if _, err = ctx.Execute(req); err != nil {
-			log.WithError(err)
-			return
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		if err = client.DisableTwoFactor(cmd.Context(), args[0]); err != nil {
+			http.HandleError(err, cfg, log)
 		}
 	},
 }
@@ -223,22 +187,22 @@ var getAccountActivityCmd = &cobra.Command{
 		log.ApplyFlags(cmd.Flags())
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("GET", "/api/client/account/activity", nil)
-		res, err := ctx.Execute(req)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		activity, meta, err := client.ListAccountActivity(cmd.Context(), util.ParseListOptions(cmd.Flags()))
 		if err != nil {
-			log.WithError(err)
+			http.HandleError(err, cfg, log)
 			return
 		}
 
-		buf, err := http.HandleDataResponse(res, cfg)
+		buf, err := http.HandleList("activity_log", activity, meta, cfg)
 		if err != nil {
 			log.WithError(err)
 			return
@@ -256,22 +220,22 @@ var getAPIKeysCmd = &cobra.Command{
 		log.ApplyFlags(cmd.Flags())
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("GET", "/api/client/account/api-keys", nil)
-		res, err := ctx.Execute(req)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		keys, err := client.ListAPIKeys(cmd.Context())
 		if err != nil {
-			log.WithError(err)
+			http.HandleError(err, cfg, log)
 			return
 		}
 
-		buf, err := http.HandleDataResponse(res, cfg)
+		buf, err := http.HandleList("api_key", keys, nil, cfg)
 		if err != nil {
 			log.WithError(err)
 			return
@@ -282,7 +246,7 @@ var getAPIKeysCmd = &cobra.Command{
 }
 
 var deleteAPIKeyCmd = &cobra.Command{
-	Use:     "account:api-keys:delete identifier",
+	Use:     "account:api-keys:delete identifier [-y]",
 	Aliases: []string{"account:apikeys:delete"},
 	Short:   "deletes an api key",
 	Run: func(cmd *cobra.Command, args []string) {
@@ -293,17 +257,300 @@ var deleteAPIKeyCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		keys, err := client.ListAPIKeys(cmd.Context())
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		var key *ptero.APIKey
+		for _, k := range keys {
+			if k.Identifier == args[0] {
+				key = k
+			}
+		}
+		if key == nil {
+			log.Error("api key '%s' not found", args[0])
+			return
+		}
+
+		// the description is checked so that a mistyped identifier can't be confirmed
+		// by typing it again
+		answer := key.Description
+		if answer == "" {
+			answer = key.Identifier
+		}
+
+		prompt := fmt.Sprintf("delete api key '%s' (%s)?", key.Description, key.Identifier)
+		if err = util.Confirm(cmd.Flags(), prompt, answer); err != nil {
+			log.WithError(err)
+			os.Exit(1)
+		}
+
+		if err = client.DeleteAPIKey(cmd.Context(), args[0]); err != nil {
+			http.HandleError(err, cfg, log)
+		}
+	},
+}
+
+var updateEmailCmd = &cobra.Command{
+	Use:   "account:email email",
+	Short: "updates the account email",
+	Long: "Updates the account email address. The account password is read from a prompt, or from\n" +
+		"stdin if it is not a terminal.",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"email"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		password, err := util.ReadPassword("password: ")
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		if password == "" {
+			log.Error("a password is required to update the email")
+			return
+		}
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		if err = client.UpdateEmail(cmd.Context(), args[0], password); err != nil {
+			http.HandleError(err, cfg, log)
+		}
+	},
+}
+
+var updatePasswordCmd = &cobra.Command{
+	Use:   "account:password",
+	Short: "updates the account password",
+	Long: "Updates the account password. The current and new passwords are read from a prompt, or\n" +
+		"from stdin (one per line) if it is not a terminal.",
+	Run: func(cmd *cobra.Command, _ []string) {
+		log.ApplyFlags(cmd.Flags())
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		current, err := util.ReadPassword("current password: ")
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		password, err := util.ReadPassword("new password: ")
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		if current == "" || password == "" {
+			log.Error("the current and new passwords are required")
+			return
+		}
+
+		if util.IsTerminal(os.Stdin) {
+			confirm, err := util.ReadPassword("confirm new password: ")
+			if err != nil {
+				log.WithError(err)
+				return
+			}
+
+			if confirm != password {
+				log.Error("the new passwords do not match")
+				return
+			}
+		}
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		if err = client.UpdatePassword(cmd.Context(), current, password); err != nil {
+			http.HandleError(err, cfg, log)
+		}
+	},
+}
+
+var createAPIKeyCmd = &cobra.Command{
+	Use:     "account:api-keys:create --description text [--allowed-ips ip,...]",
+	Aliases: []string{"account:apikeys:create"},
+	Short:   "creates an api key",
+	Run: func(cmd *cobra.Command, _ []string) {
+		log.ApplyFlags(cmd.Flags())
+
+		description, _ := cmd.Flags().GetString("description")
+		if description == "" {
+			log.Error("a description is required to create an api key")
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		ips, _ := cmd.Flags().GetStringSlice("allowed-ips")
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		key, err := client.CreateAPIKey(cmd.Context(), description, ips)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		buf, err := http.HandleItem("api_key", key, cfg)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		log.LineB(buf)
+		log.Warn("the api key token cannot be retrieved again, make sure to store it safely")
+	},
+}
+
+var getSSHKeysCmd = &cobra.Command{
+	Use:     "account:ssh-keys:get",
+	Aliases: []string{"account:sshkeys:get"},
+	Short:   "gets the account ssh keys",
+	Run: func(cmd *cobra.Command, _ []string) {
+		log.ApplyFlags(cmd.Flags())
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		keys, err := client.ListSSHKeys(cmd.Context())
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		buf, err := http.HandleList("ssh_key", keys, nil, cfg)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		log.LineB(buf)
+	},
+}
+
+var createSSHKeyCmd = &cobra.Command{
+	Use:     "account:ssh-keys:create name [public-key] [--file path]",
+	Aliases: []string{"account:sshkeys:create"},
+	Short:   "adds an ssh key to the account",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgsOverflow(args, []string{"name"}, 1); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		var key string
+		file, _ := cmd.Flags().GetString("file")
+
+		switch {
+		case file != "" && len(args) == 2:
+			log.Error("only one of the public key argument or the '--file' flag can be specified")
+			return
+		case file != "":
+			buf, err := util.SafeReadFile(file)
+			if err != nil {
+				log.Error("failed to read public key file:").WithError(err)
+				return
+			}
+
+			key = strings.TrimSpace(string(buf))
+		case len(args) == 2:
+			key = args[1]
+		default:
+			log.Error("a public key or the '--file' flag must be specified")
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("DELETE", "/api/client/account/api-keys/"+args[0], nil)
-		if _, err := ctx.Execute(req); err != nil {
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		ssh, err := client.CreateSSHKey(cmd.Context(), args[0], key)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		buf, err := http.HandleItem("ssh_key", ssh, cfg)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		log.LineB(buf)
+	},
+}
+
+var deleteSSHKeyCmd = &cobra.Command{
+	Use:     "account:ssh-keys:delete fingerprint",
+	Aliases: []string{"account:sshkeys:delete"},
+	Short:   "removes an ssh key from the account",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"fingerprint"}); err != nil {
 			log.WithError(err)
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		if err = client.DeleteSSHKey(cmd.Context(), args[0]); err != nil {
+			http.HandleError(err, cfg, log)
 		}
 	},
 }
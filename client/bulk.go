@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"os"
+
+	"github.com/pteropackages/soar/ptero"
+	"github.com/pteropackages/soar/util"
+	"github.com/spf13/cobra"
+)
+
+var serverAliases = map[string]string{"image": "docker_image"}
+
+func serverTargets(cmd *cobra.Command, client *ptero.Client, args []string) ([]string, error) {
+	selector, ids, err := util.ParseBulkFlags(cmd.Flags(), args)
+	if err != nil || selector == nil {
+		return ids, err
+	}
+
+	servers, _, err := client.ListServers(cmd.Context(), &ptero.ListOptions{All: true})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range servers {
+		ok, err := selector.Matches(s, serverAliases)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			ids = append(ids, s.Identifier)
+		}
+	}
+
+	return ids, nil
+}
+
+// runBulk runs the action on each of the servers and exits with a non-zero status
+// code if any of them failed.
+func runBulk(cmd *cobra.Command, ids []string, action func(ctx context.Context, id string) error) {
+	if len(ids) == 0 {
+		log.Warn("no servers matched the selector")
+		return
+	}
+
+	// request logs would break up the results
+	log.Quiet = true
+
+	parallel, _ := cmd.Flags().GetInt("parallel")
+	ok := util.RunBulk(log, "server", ids, parallel, func(i int) error {
+		return action(cmd.Context(), ids[i])
+	})
+	if !ok {
+		os.Exit(1)
+	}
+}
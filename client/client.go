@@ -1,6 +1,8 @@
 package client
 
 import (
+	"time"
+
 	"github.com/pteropackages/soar/logger"
 	"github.com/pteropackages/soar/util"
 	"github.com/spf13/cobra"
@@ -22,14 +24,26 @@ func GroupCommands() *cobra.Command {
 	util.ApplyDefaultFlags(enableTwoFactorCmd)
 	util.ApplyDefaultFlags(disableTwoFactorCmd)
 	util.ApplyDefaultFlags(getAccountActivityCmd)
+	util.ApplyDefaultFlags(updateEmailCmd)
+	util.ApplyDefaultFlags(updatePasswordCmd)
 	util.ApplyDefaultFlags(getAPIKeysCmd)
+	util.ApplyDefaultFlags(createAPIKeyCmd)
 	util.ApplyDefaultFlags(deleteAPIKeyCmd)
+	util.ApplyDefaultFlags(getSSHKeysCmd)
+	util.ApplyDefaultFlags(createSSHKeyCmd)
+	util.ApplyDefaultFlags(deleteSSHKeyCmd)
 	util.ApplyDefaultFlags(getServerWSCmd)
 	util.ApplyDefaultFlags(getServerResourcesCmd)
+	util.ApplyDefaultFlags(serverTopCmd)
 	util.ApplyDefaultFlags(getServerActivityCmd)
 	util.ApplyDefaultFlags(sendServerCommandCmd)
 	util.ApplyDefaultFlags(setServerPowerStateCmd)
+	util.ApplyDefaultFlags(waitServerCmd)
+	util.ApplyDefaultFlags(serverConsoleCmd)
 	util.ApplyDefaultFlags(getDatabasesCmd)
+	util.ApplyDefaultFlags(createDatabaseCmd)
+	util.ApplyDefaultFlags(rotateDatabasePasswordCmd)
+	util.ApplyDefaultFlags(deleteDatabaseCmd)
 	util.ApplyDefaultFlags(listFilesCmd)
 	util.ApplyDefaultFlags(getFileInfoCmd)
 	util.ApplyDefaultFlags(getFileContentsCmd)
@@ -45,6 +59,30 @@ func GroupCommands() *cobra.Command {
 	util.ApplyDefaultFlags(chmodFileCmd)
 	util.ApplyDefaultFlags(pullFileCmd)
 	util.ApplyDefaultFlags(uploadFilesCmd)
+	util.ApplyDefaultFlags(syncFilesCmd)
+	util.ApplyDefaultFlags(listBackupsCmd)
+	util.ApplyDefaultFlags(createBackupCmd)
+	util.ApplyDefaultFlags(getBackupCmd)
+	util.ApplyDefaultFlags(downloadBackupCmd)
+	util.ApplyDefaultFlags(lockBackupCmd)
+	util.ApplyDefaultFlags(restoreBackupCmd)
+	util.ApplyDefaultFlags(deleteBackupCmd)
+	util.ApplyDefaultFlags(listAllocationsCmd)
+	util.ApplyDefaultFlags(assignAllocationCmd)
+	util.ApplyDefaultFlags(setAllocationNoteCmd)
+	util.ApplyDefaultFlags(setPrimaryAllocationCmd)
+	util.ApplyDefaultFlags(unassignAllocationCmd)
+	util.ApplyDefaultFlags(listSchedulesCmd)
+	util.ApplyDefaultFlags(getScheduleCmd)
+	util.ApplyDefaultFlags(createScheduleCmd)
+	util.ApplyDefaultFlags(updateScheduleCmd)
+	util.ApplyDefaultFlags(deleteScheduleCmd)
+	util.ApplyDefaultFlags(executeScheduleCmd)
+	util.ApplyDefaultFlags(createTaskCmd)
+	util.ApplyDefaultFlags(updateTaskCmd)
+	util.ApplyDefaultFlags(deleteTaskCmd)
+	util.ApplyDefaultFlags(exportSchedulesCmd)
+	util.ApplyDefaultFlags(importSchedulesCmd)
 	util.ApplyDefaultFlags(getSubUsersCmd)
 	util.ApplyDefaultFlags(addSubUserCmd)
 	util.ApplyDefaultFlags(removeSubUserCmd)
@@ -54,7 +92,36 @@ func GroupCommands() *cobra.Command {
 	util.ApplyDefaultFlags(reinstallServerCmd)
 	util.ApplyDefaultFlags(setDockerImageCmd)
 
+	util.ApplyFilterFlags(getServersCmd)
+	util.ApplyFilterFlags(getAccountActivityCmd)
+	util.ApplyFilterFlags(getServerActivityCmd)
+	util.ApplyFilterFlags(listBackupsCmd)
+
+	util.ApplyDataFlags(createScheduleCmd)
+	util.ApplyDataFlags(updateScheduleCmd)
+	util.ApplyDataFlags(createTaskCmd)
+	util.ApplyDataFlags(updateTaskCmd)
+
+	util.ApplyWaitFlags(setServerPowerStateCmd)
+	util.ApplyWaitFlags(reinstallServerCmd)
+
+	util.ApplyBulkFlags(setServerPowerStateCmd)
+
+	util.ApplyConfirmFlags(deleteAPIKeyCmd)
+	util.ApplyConfirmFlags(deleteFilesCmd)
+	util.ApplyConfirmFlags(reinstallServerCmd)
+
 	getServersCmd.Flags().String("id", "", "the identifier of the server")
+	createAPIKeyCmd.Flags().String("description", "", "the description of the api key")
+	createAPIKeyCmd.Flags().StringSlice("allowed-ips", nil, "the ips allowed to use the api key")
+	createSSHKeyCmd.Flags().String("file", "", "the path to a public key file")
+	serverTopCmd.Flags().Duration("interval", 2*time.Second, "the time between refreshes")
+	serverTopCmd.Flags().Bool("once", false, "print the resource usage once and exit")
+	waitServerCmd.Flags().String("state", "", "the state to wait for (running, offline or installed)")
+	waitServerCmd.Flags().Duration("timeout", 5*time.Minute, "the maximum time to wait for")
+	getDatabasesCmd.Flags().Bool("include-password", false, "include the database passwords")
+	createDatabaseCmd.Flags().String("remote", "%", "the address to allow connections from")
+	rotateDatabasePasswordCmd.Flags().String("connection-string", "", "print a connection string in the given format (jdbc or mysql)")
 	listFilesCmd.Flags().BoolP("dir", "d", false, "only list directories")
 	listFilesCmd.Flags().BoolP("file", "f", false, "only list files")
 	listFilesCmd.Flags().String("root", "/", "the root directory")
@@ -72,6 +139,16 @@ func GroupCommands() *cobra.Command {
 	pullFileCmd.Flags().BoolP("foreground", "f", false, "pull the file in the foreground")
 	uploadFilesCmd.Flags().String("dest", "", "the path to upload files to")
 	uploadFilesCmd.Flags().BoolP("url-only", "U", false, "only return the url")
+	syncFilesCmd.Flags().String("direction", "up", "the direction to sync files (up, down)")
+	syncFilesCmd.Flags().Bool("delete", false, "delete files that don't exist in the source")
+	createBackupCmd.Flags().String("name", "", "the name of the backup")
+	createBackupCmd.Flags().StringArray("ignore", nil, "a file path to ignore in the backup")
+	createBackupCmd.Flags().Bool("locked", false, "lock the backup to prevent deletion")
+	downloadBackupCmd.Flags().String("dest", "", "the path to save the backup at")
+	downloadBackupCmd.Flags().BoolP("url-only", "U", false, "only return the url")
+	restoreBackupCmd.Flags().Bool("truncate", false, "delete all server files before restoring")
+	exportSchedulesCmd.Flags().String("dest", "", "the path to save the schedules at")
+	importSchedulesCmd.Flags().Bool("replace", false, "replace existing schedules with the same name")
 	getSubUsersCmd.Flags().String("uuid", "", "the uuid of the subuser")
 
 	cmd.AddCommand(getAccountCmd)
@@ -81,14 +158,26 @@ func GroupCommands() *cobra.Command {
 	cmd.AddCommand(enableTwoFactorCmd)
 	cmd.AddCommand(disableTwoFactorCmd)
 	cmd.AddCommand(getAccountActivityCmd)
+	cmd.AddCommand(updateEmailCmd)
+	cmd.AddCommand(updatePasswordCmd)
 	cmd.AddCommand(getAPIKeysCmd)
+	cmd.AddCommand(createAPIKeyCmd)
 	cmd.AddCommand(deleteAPIKeyCmd)
+	cmd.AddCommand(getSSHKeysCmd)
+	cmd.AddCommand(createSSHKeyCmd)
+	cmd.AddCommand(deleteSSHKeyCmd)
 	cmd.AddCommand(getServerWSCmd)
 	cmd.AddCommand(getServerResourcesCmd)
+	cmd.AddCommand(serverTopCmd)
 	cmd.AddCommand(getServerActivityCmd)
 	cmd.AddCommand(sendServerCommandCmd)
 	cmd.AddCommand(setServerPowerStateCmd)
+	cmd.AddCommand(waitServerCmd)
+	cmd.AddCommand(serverConsoleCmd)
 	cmd.AddCommand(getDatabasesCmd)
+	cmd.AddCommand(createDatabaseCmd)
+	cmd.AddCommand(rotateDatabasePasswordCmd)
+	cmd.AddCommand(deleteDatabaseCmd)
 	cmd.AddCommand(listFilesCmd)
 	cmd.AddCommand(getFileInfoCmd)
 	cmd.AddCommand(getFileContentsCmd)
@@ -105,6 +194,30 @@ func GroupCommands() *cobra.Command {
 	cmd.AddCommand(chmodFileCmd)
 	cmd.AddCommand(pullFileCmd)
 	cmd.AddCommand(uploadFilesCmd)
+	cmd.AddCommand(syncFilesCmd)
+	cmd.AddCommand(listBackupsCmd)
+	cmd.AddCommand(createBackupCmd)
+	cmd.AddCommand(getBackupCmd)
+	cmd.AddCommand(downloadBackupCmd)
+	cmd.AddCommand(lockBackupCmd)
+	cmd.AddCommand(restoreBackupCmd)
+	cmd.AddCommand(deleteBackupCmd)
+	cmd.AddCommand(listAllocationsCmd)
+	cmd.AddCommand(assignAllocationCmd)
+	cmd.AddCommand(setAllocationNoteCmd)
+	cmd.AddCommand(setPrimaryAllocationCmd)
+	cmd.AddCommand(unassignAllocationCmd)
+	cmd.AddCommand(listSchedulesCmd)
+	cmd.AddCommand(getScheduleCmd)
+	cmd.AddCommand(createScheduleCmd)
+	cmd.AddCommand(updateScheduleCmd)
+	cmd.AddCommand(deleteScheduleCmd)
+	cmd.AddCommand(executeScheduleCmd)
+	cmd.AddCommand(createTaskCmd)
+	cmd.AddCommand(updateTaskCmd)
+	cmd.AddCommand(deleteTaskCmd)
+	cmd.AddCommand(exportSchedulesCmd)
+	cmd.AddCommand(importSchedulesCmd)
 	cmd.AddCommand(getSubUsersCmd)
 	cmd.AddCommand(addSubUserCmd)
 	cmd.AddCommand(removeSubUserCmd)
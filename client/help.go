@@ -0,0 +1,36 @@
+package client
+
+var createScheduleHelp = "Creates a schedule for a server using the data provided from one of the following options:\n" +
+	"'--data source' - takes a set of key-value pairs for arguments (e.g. \"name=restart minute=0 hour=4\")\n" +
+	"'--data-file file' - takes a file path to a JSON file with the data fields\n" +
+	"'--data-json source' - takes a raw JSON data input\n\n" +
+	"The name field is required. The minute, hour, day_of_month, month and day_of_week cron fields\n" +
+	"default to '*', is_active defaults to true and only_when_online defaults to false."
+
+var updateScheduleHelp = "Updates a server schedule using the data provided from one of the following options:\n" +
+	"'--data source' - takes a set of key-value pairs for arguments (e.g. \"hour=6 is_active=false\")\n" +
+	"'--data-file file' - takes a file path to a JSON file with the data fields\n" +
+	"'--data-json source' - takes a raw JSON data input\n\n" +
+	"All fields are optional; fields that are not specified are kept from the current schedule.\n" +
+	"The available fields are name, minute, hour, day_of_month, month, day_of_week, is_active and\n" +
+	"only_when_online."
+
+var createTaskHelp = "Creates a task for a server schedule using the data provided from one of the following options:\n" +
+	"'--data source' - takes a set of key-value pairs for arguments (e.g. 'action=command payload=\"say hi\"')\n" +
+	"'--data-file file' - takes a file path to a JSON file with the data fields\n" +
+	"'--data-json source' - takes a raw JSON data input\n\n" +
+	"The action field is required and must be one of command, power or backup. The payload is the\n" +
+	"command to send, the power action, or the files to ignore for backups. The time_offset field is the\n" +
+	"number of seconds to wait after the previous task (max 900) and continue_on_failure defaults to false."
+
+var updateTaskHelp = "Updates a schedule task using the data provided from one of the following options:\n" +
+	"'--data source' - takes a set of key-value pairs for arguments (e.g. \"time_offset=30\")\n" +
+	"'--data-file file' - takes a file path to a JSON file with the data fields\n" +
+	"'--data-json source' - takes a raw JSON data input\n\n" +
+	"All fields are optional; fields that are not specified are kept from the current task.\n" +
+	"The available fields are action, payload, time_offset and continue_on_failure."
+
+var importSchedulesHelp = "Imports schedules and their tasks from a YAML file (see the schedules:export command).\n" +
+	"Each schedule is created alongside the existing schedules on the server unless the --replace flag\n" +
+	"is specified, which deletes existing schedules with the same name once the new schedule and its\n" +
+	"tasks have been created. All schedules are validated before anything is changed."
@@ -0,0 +1,287 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pteropackages/soar/config"
+	"github.com/pteropackages/soar/http"
+	"github.com/pteropackages/soar/ptero"
+	"github.com/pteropackages/soar/util"
+	"github.com/spf13/cobra"
+)
+
+var listBackupsCmd = &cobra.Command{
+	Use:     "backups:list identifier",
+	Aliases: []string{"backups:ls"},
+	Short:   "lists the server backups",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		backups, meta, err := client.ListBackups(cmd.Context(), args[0], util.ParseListOptions(cmd.Flags()))
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		buf, err := http.HandleList("backup", backups, meta, cfg)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		log.LineB(buf)
+	},
+}
+
+var createBackupCmd = &cobra.Command{
+	Use:   "backups:create identifier [--name name] [--ignore path] [--locked]",
+	Short: "creates a server backup",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		var fields ptero.CreateBackupDescriptor
+		fields.Name, _ = cmd.Flags().GetString("name")
+		fields.IsLocked, _ = cmd.Flags().GetBool("locked")
+		ignored, _ := cmd.Flags().GetStringArray("ignore")
+		fields.Ignored = strings.Join(ignored, "\n")
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		backup, err := client.CreateBackup(cmd.Context(), args[0], fields)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		buf, err := http.HandleItem("backup", backup, cfg)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		log.LineB(buf)
+	},
+}
+
+var getBackupCmd = &cobra.Command{
+	Use:   "backups:info identifier uuid",
+	Short: "gets information about a server backup",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier", "uuid"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		backup, err := client.GetBackup(cmd.Context(), args[0], args[1])
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		buf, err := http.HandleItem("backup", backup, cfg)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		log.LineB(buf)
+	},
+}
+
+var downloadBackupCmd = &cobra.Command{
+	Use:     "backups:download identifier uuid [--dest path] [-U | --url-only]",
+	Aliases: []string{"backups:down"},
+	Short:   "downloads a server backup or returns the url",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier", "uuid"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		dest, _ := cmd.Flags().GetString("dest")
+		if dest == "" {
+			cwd, _ := os.Getwd()
+			dest = filepath.Join(cwd, args[1]+".tar.gz")
+		}
+
+		skip, _ := cmd.Flags().GetBool("url-only")
+
+		_, err := os.Stat(dest)
+		if err == nil && !skip {
+			log.Error("destination path already exists")
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		location, err := client.GetBackupDownloadURL(cmd.Context(), args[0], args[1])
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		if skip {
+			log.Line("%s", location)
+			return
+		}
+
+		backup, err := client.GetBackup(cmd.Context(), args[0], args[1])
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		if ptero.CanResumeDownload(dest, backup.Version()) {
+			log.Ignore().Info("resuming partial download")
+		}
+
+		progress := util.NewProgress(filepath.Base(dest), log.Quiet)
+		err = client.DownloadFile(cmd.Context(), location, dest, backup.Version(), progress)
+		progress.Done()
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			if _, err = os.Stat(dest + ".part"); err == nil {
+				log.Ignore().Info("run the command again to resume the download")
+			}
+		}
+	},
+}
+
+var lockBackupCmd = &cobra.Command{
+	Use:     "backups:lock identifier uuid",
+	Aliases: []string{"backups:unlock"},
+	Short:   "toggles the lock on a server backup",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier", "uuid"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		backup, err := client.ToggleBackupLock(cmd.Context(), args[0], args[1])
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		buf, err := http.HandleItem("backup", backup, cfg)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		log.LineB(buf)
+	},
+}
+
+var restoreBackupCmd = &cobra.Command{
+	Use:   "backups:restore identifier uuid [--truncate]",
+	Short: "restores a server backup",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier", "uuid"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		truncate, _ := cmd.Flags().GetBool("truncate")
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		if err = client.RestoreBackup(cmd.Context(), args[0], args[1], truncate); err != nil {
+			http.HandleError(err, cfg, log)
+		}
+	},
+}
+
+var deleteBackupCmd = &cobra.Command{
+	Use:     "backups:delete identifier uuid",
+	Aliases: []string{"backups:rm"},
+	Short:   "deletes a server backup",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier", "uuid"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		if err = client.DeleteBackup(cmd.Context(), args[0], args[1]); err != nil {
+			http.HandleError(err, cfg, log)
+		}
+	},
+}
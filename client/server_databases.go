@@ -3,12 +3,13 @@ package client
 import (
 	"github.com/pteropackages/soar/config"
 	"github.com/pteropackages/soar/http"
+	"github.com/pteropackages/soar/ptero"
 	"github.com/pteropackages/soar/util"
 	"github.com/spf13/cobra"
 )
 
 var getDatabasesCmd = &cobra.Command{
-	Use:     "databases:get identifier",
+	Use:     "databases:get identifier [--include-password]",
 	Aliases: []string{"database:get", "db:get"},
 	Short:   "gets server databases",
 	Run: func(cmd *cobra.Command, args []string) {
@@ -19,22 +20,127 @@ var getDatabasesCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("GET", "/api/client/servers/"+args[0]+"/databases", nil)
-		res, err := ctx.Execute(req)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		opts := &ptero.ListOptions{}
+		if include, _ := cmd.Flags().GetBool("include-password"); include {
+			opts.Include = []string{"password"}
+		}
+
+		databases, err := client.ListDatabases(cmd.Context(), args[0], opts)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		buf, err := http.HandleList("server_database", databases, nil, cfg)
 		if err != nil {
 			log.WithError(err)
 			return
 		}
 
-		buf, err := http.HandleDataResponse(res, cfg)
+		log.LineB(buf)
+	},
+}
+
+var createDatabaseCmd = &cobra.Command{
+	Use:     "databases:create identifier name [--remote address]",
+	Aliases: []string{"database:create", "db:create"},
+	Short:   "creates a server database",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier", "name"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		fields := ptero.CreateDatabaseDescriptor{Database: args[1]}
+		fields.Remote, _ = cmd.Flags().GetString("remote")
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		database, err := client.CreateDatabase(cmd.Context(), args[0], fields)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		buf, err := http.HandleItem("server_database", database, cfg)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		log.LineB(buf)
+	},
+}
+
+var rotateDatabasePasswordCmd = &cobra.Command{
+	Use:     "databases:rotate identifier id [--connection-string format]",
+	Aliases: []string{"database:rotate", "db:rotate"},
+	Short:   "rotates the password of a server database",
+	Long: "Rotates the password of a server database. Use the --connection-string flag to print a\n" +
+		"connection string with the new credentials instead, in either the 'jdbc' or 'mysql' format.",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier", "id"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		format, _ := cmd.Flags().GetString("connection-string")
+		switch format {
+		case "":
+		case "jdbc":
+		case "mysql":
+		default:
+			log.Error("invalid connection string format '%s'", format)
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		database, err := client.RotateDatabasePassword(cmd.Context(), args[0], args[1])
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		if format != "" {
+			conn, err := database.ConnectionString(format)
+			if err != nil {
+				log.WithError(err)
+				return
+			}
+
+			log.Line("%s", conn)
+			return
+		}
+
+		buf, err := http.HandleItem("server_database", database, cfg)
 		if err != nil {
 			log.WithError(err)
 			return
@@ -43,3 +149,30 @@ var getDatabasesCmd = &cobra.Command{
 		log.LineB(buf)
 	},
 }
+
+var deleteDatabaseCmd = &cobra.Command{
+	Use:     "databases:delete identifier id",
+	Aliases: []string{"database:delete", "db:delete"},
+	Short:   "deletes a server database",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier", "id"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		if err = client.DeleteDatabase(cmd.Context(), args[0], args[1]); err != nil {
+			http.HandleError(err, cfg, log)
+		}
+	},
+}
@@ -1,43 +1,20 @@
 package client
 
 import (
-	"bytes"
-	"encoding/json"
-	"io"
-	"mime/multipart"
+	"fmt"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/pteropackages/soar/config"
 	"github.com/pteropackages/soar/http"
+	"github.com/pteropackages/soar/ptero"
 	"github.com/pteropackages/soar/util"
 	"github.com/spf13/cobra"
 )
 
-type file struct {
-	Name       string `json:"name"`
-	Size       int64  `json:"size"`
-	Mode       string `json:"mode"`
-	ModeBits   string `json:"mode_bits"`
-	MimeType   string `json:"mimetype"`
-	IsFile     bool   `json:"is_file"`
-	IsSymlink  bool   `json:"is_symlink"`
-	CreatedAt  string `json:"created_at"`
-	ModifiedAt string `json:"modified_at"`
-}
-
-type fractalFile struct {
-	O string `json:"object"`
-	A *file  `json:"attributes"`
-}
-
-type fractalFileList struct {
-	O string        `json:"object"`
-	D []fractalFile `json:"data"`
-}
-
 var listFilesCmd = &cobra.Command{
 	Use:     "files:list identifier [-d | --dir] [-f | --file] [--root dir]",
 	Aliases: []string{"files:ls", "files:dir"},
@@ -50,7 +27,8 @@ var listFilesCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
@@ -58,14 +36,10 @@ var listFilesCmd = &cobra.Command{
 		cfg.ApplyFlags(cmd.Flags())
 
 		root, _ := cmd.Flags().GetString("root")
-		path := "/api/client/servers/" + args[0] + "/files/list?list&directory="
-		path += url.QueryEscape(root)
-
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("GET", path, nil)
-		res, err := ctx.Execute(req)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		files, err := client.ListFiles(cmd.Context(), args[0], root)
 		if err != nil {
-			log.WithError(err)
+			http.HandleError(err, cfg, log)
 			return
 		}
 
@@ -73,68 +47,23 @@ var listFilesCmd = &cobra.Command{
 		dirOnly, _ := cmd.Flags().GetBool("dir")
 
 		if fileOnly || dirOnly {
-			var model fractalFileList
-
-			if err = json.Unmarshal(res, &model); err != nil {
-				log.WithError(err)
-				return
-			}
-
-			var filtered fractalFileList
-			filtered.O = "list"
-
-			if fileOnly {
-				for _, file := range model.D {
-					if file.A.MimeType == "inode/directory" {
-						continue
-					}
-
-					filtered.D = append(filtered.D, file)
+			filtered := make([]*ptero.File, 0, len(files))
+			for _, file := range files {
+				if file.IsDir() == dirOnly {
+					filtered = append(filtered, file)
 				}
-			} else {
-				for _, file := range model.D {
-					if file.A.MimeType == "inode/directory" {
-						filtered.D = append(filtered.D, file)
-					}
-				}
-			}
-
-			var buf []byte
-
-			if cfg.Http.ParseBody {
-				inner := make([]*file, 0, len(filtered.D))
-				for _, file := range filtered.D {
-					inner = append(inner, file.A)
-				}
-
-				if cfg.Http.ParseIndent {
-					buf, err = json.MarshalIndent(inner, "", "  ")
-				} else {
-					buf, err = json.Marshal(inner)
-				}
-			} else {
-				if cfg.Http.ParseIndent {
-					buf, err = json.MarshalIndent(filtered, "", "  ")
-				} else {
-					buf, err = json.Marshal(filtered)
-				}
-			}
-
-			if err != nil {
-				log.WithError(err)
-				return
 			}
 
-			log.LineB(buf)
-		} else {
-			buf, err := http.HandleDataResponse(res, cfg)
-			if err != nil {
-				log.WithError(err)
-				return
-			}
+			files = filtered
+		}
 
-			log.LineB(buf)
+		buf, err := http.HandleList("file_object", files, nil, cfg)
+		if err != nil {
+			log.WithError(err)
+			return
 		}
+
+		log.LineB(buf)
 	},
 }
 
@@ -150,56 +79,35 @@ var getFileInfoCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("GET", "/api/client/servers/"+args[0]+"/files/list", nil)
-		res, err := ctx.Execute(req)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		files, err := client.ListFiles(cmd.Context(), args[0], "")
 		if err != nil {
-			log.WithError(err)
-			return
-		}
-
-		var model fractalFileList
-		if err = json.Unmarshal(res, &model); err != nil {
-			log.WithError(err)
+			http.HandleError(err, cfg, log)
 			return
 		}
 
-		target := fractalFile{}
-		for _, file := range model.D {
-			if file.A.Name == args[1] {
+		var target *ptero.File
+		for _, file := range files {
+			if file.Name == args[1] {
 				target = file
 				break
 			}
 		}
 
-		if target == (fractalFile{}) {
+		if target == nil {
 			log.Error("file not found")
 			return
 		}
 
-		var buf []byte
-
-		if cfg.Http.ParseBody {
-			if cfg.Http.ParseIndent {
-				buf, err = json.MarshalIndent(target.A, "", "  ")
-			} else {
-				buf, err = json.Marshal(target.A)
-			}
-		} else {
-			if cfg.Http.ParseIndent {
-				buf, err = json.MarshalIndent(target, "", "  ")
-			} else {
-				buf, err = json.Marshal(target)
-			}
-		}
-
+		buf, err := http.HandleItem("file_object", target, cfg)
 		if err != nil {
 			log.WithError(err)
 			return
@@ -221,23 +129,18 @@ var getFileContentsCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		path := "/api/client/servers/" + args[0] + "/files/contents?file="
-		path += url.QueryEscape(args[1])
-
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("GET", path, nil)
-		req.Header.Set("Accept", "text/plain")
-
-		res, err := ctx.Execute(req)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		res, err := client.GetFileContents(cmd.Context(), args[0], args[1])
 		if err != nil {
-			log.WithError(err)
+			http.HandleError(err, cfg, log)
 			return
 		}
 
@@ -271,56 +174,45 @@ var downloadFileCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		path := "/api/client/servers/" + args[0] + "/files/download?file="
-		path += url.QueryEscape(args[1])
-
-		ctx := http.New(cfg, &cfg.Client, log)
-		urlReq := ctx.Request("GET", path, nil)
-		res, err := ctx.Execute(urlReq)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		location, err := client.GetFileDownloadURL(cmd.Context(), args[0], args[1])
 		if err != nil {
-			log.WithError(err)
-			return
-		}
-
-		var model struct {
-			Attributes struct {
-				URL string `json:"url"`
-			} `json:"attributes"`
-		}
-		if err = json.Unmarshal(res, &model); err != nil {
-			log.WithError(err)
+			http.HandleError(err, cfg, log)
 			return
 		}
 
 		if skip {
-			log.Line(model.Attributes.URL)
+			log.Line("%s", location)
 			return
 		}
 
-		dlReq := http.Request("GET", model.Attributes.URL, nil)
-		dlReq.Header.Set("Accept", "application/octet-stream")
-		res, err = ctx.Execute(dlReq)
+		file, err := client.GetFile(cmd.Context(), args[0], args[1])
 		if err != nil {
-			log.WithError(err)
+			http.HandleError(err, cfg, log)
 			return
 		}
 
-		file, err := os.Create(dest)
-		if err != nil {
-			log.Error("failed to create file:").WithError(err)
-			return
+		if ptero.CanResumeDownload(dest, file.Version()) {
+			log.Ignore().Info("resuming partial download")
 		}
 
-		log.Debug("attempting file write")
-		defer file.Close()
-		file.Write(res)
+		progress := util.NewProgress(filepath.Base(dest), log.Quiet)
+		err = client.DownloadFile(cmd.Context(), location, dest, file.Version(), progress)
+		progress.Done()
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			if _, err = os.Stat(dest + ".part"); err == nil {
+				log.Ignore().Info("run the command again to resume the download")
+			}
+		}
 	},
 }
 
@@ -336,7 +228,8 @@ var renameFileCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
@@ -344,16 +237,9 @@ var renameFileCmd = &cobra.Command{
 		cfg.ApplyFlags(cmd.Flags())
 
 		root, _ := cmd.Flags().GetString("root")
-		info := map[string]string{"from": args[1], "to": args[2]}
-		data, _ := json.Marshal(map[string]interface{}{"root": root, "files": info})
-
-		body := bytes.Buffer{}
-		body.Write(data)
-
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("PUT", "/api/client/servers/"+args[0]+"/files/rename", &body)
-		if _, err = ctx.Execute(req); err != nil {
-			log.WithError(err)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		if err = client.RenameFile(cmd.Context(), args[0], root, args[1], args[2]); err != nil {
+			http.HandleError(err, cfg, log)
 		}
 	},
 }
@@ -370,21 +256,17 @@ var copyFileCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		data, _ := json.Marshal(map[string]string{"location": args[1]})
-		body := bytes.Buffer{}
-		body.Write(data)
-
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("POST", "/api/client/servers/"+args[0]+"/files/copy", &body)
-		if _, err = ctx.Execute(req); err != nil {
-			log.WithError(err)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		if err = client.CopyFile(cmd.Context(), args[0], args[1]); err != nil {
+			http.HandleError(err, cfg, log)
 		}
 	},
 }
@@ -406,24 +288,17 @@ var writeFileCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		body := bytes.Buffer{}
-		body.Write([]byte(args[2]))
-
-		path := "/api/client/servers/" + args[0] + "/files/write?file="
-		path += url.QueryEscape(args[1])
-
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("POST", path, &body)
-		req.Header.Set("Content-Type", "text/plain")
-		if _, err = ctx.Execute(req); err != nil {
-			log.WithError(err)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		if err = client.WriteFile(cmd.Context(), args[0], args[1], []byte(args[2])); err != nil {
+			http.HandleError(err, cfg, log)
 		}
 	},
 }
@@ -447,20 +322,17 @@ var createFileCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		path := "/api/client/servers/" + args[0] + "/files/write?file="
-		path += url.QueryEscape(args[1])
-
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("POST", path, nil)
-		if _, err = ctx.Execute(req); err != nil {
-			log.WithError(err)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		if err = client.WriteFile(cmd.Context(), args[0], args[1], nil); err != nil {
+			http.HandleError(err, cfg, log)
 		}
 	},
 }
@@ -482,7 +354,8 @@ var compressFilesCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
@@ -490,19 +363,14 @@ var compressFilesCmd = &cobra.Command{
 		cfg.ApplyFlags(cmd.Flags())
 
 		root, _ := cmd.Flags().GetString("root")
-		data, _ := json.Marshal(map[string]interface{}{"root": root, "files": args[1:]})
-		body := bytes.Buffer{}
-		body.Write(data)
-
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("POST", "/api/client/servers/"+args[0]+"/files/compress", &body)
-		res, err := ctx.Execute(req)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		file, err := client.CompressFiles(cmd.Context(), args[0], root, args[1:])
 		if err != nil {
-			log.WithError(err)
+			http.HandleError(err, cfg, log)
 			return
 		}
 
-		buf, err := http.HandleItemResponse(res, cfg)
+		buf, err := http.HandleItem("file_object", file, cfg)
 		if err != nil {
 			log.WithError(err)
 			return
@@ -524,7 +392,8 @@ var decompressFileCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
@@ -532,20 +401,15 @@ var decompressFileCmd = &cobra.Command{
 		cfg.ApplyFlags(cmd.Flags())
 
 		root, _ := cmd.Flags().GetString("root")
-		data, _ := json.Marshal(map[string]string{"root": root, "file": args[1]})
-		body := bytes.Buffer{}
-		body.Write(data)
-
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("POST", "/api/client/servers/"+args[0]+"/files/decompress", &body)
-		if _, err = ctx.Execute(req); err != nil {
-			log.WithError(err)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		if err = client.DecompressFile(cmd.Context(), args[0], root, args[1]); err != nil {
+			http.HandleError(err, cfg, log)
 		}
 	},
 }
 
 var deleteFilesCmd = &cobra.Command{
-	Use:     "files:delete identifer files... [--root dir]",
+	Use:     "files:delete identifer files... [--root dir] [-y]",
 	Aliases: []string{"files:rm"},
 	Short:   "deletes one or more files",
 	Run: func(cmd *cobra.Command, args []string) {
@@ -561,7 +425,8 @@ var deleteFilesCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
@@ -569,14 +434,22 @@ var deleteFilesCmd = &cobra.Command{
 		cfg.ApplyFlags(cmd.Flags())
 
 		root, _ := cmd.Flags().GetString("root")
-		data, _ := json.Marshal(map[string]interface{}{"root": root, "files": args[1:]})
-		body := bytes.Buffer{}
-		body.Write(data)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		server, err := client.GetServer(cmd.Context(), args[0])
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
 
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("POST", "/api/client/servers/"+args[0]+"/files/delete", &body)
-		if _, err = ctx.Execute(req); err != nil {
+		prompt := fmt.Sprintf("delete %d file(s) from server '%s' (%s): %s?",
+			len(args)-1, server.Name, server.Identifier, strings.Join(args[1:], ", "))
+		if err = util.Confirm(cmd.Flags(), prompt, server.Name); err != nil {
 			log.WithError(err)
+			os.Exit(1)
+		}
+
+		if err = client.DeleteFiles(cmd.Context(), args[0], root, args[1:]); err != nil {
+			http.HandleError(err, cfg, log)
 		}
 	},
 }
@@ -593,7 +466,8 @@ var createFolderCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
@@ -601,14 +475,9 @@ var createFolderCmd = &cobra.Command{
 		cfg.ApplyFlags(cmd.Flags())
 
 		root, _ := cmd.Flags().GetString("root")
-		data, _ := json.Marshal(map[string]string{"root": root, "name": args[1]})
-		body := bytes.Buffer{}
-		body.Write(data)
-
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("POST", "/api/client/servers/"+args[0]+"/files/create-folder", &body)
-		if _, err = ctx.Execute(req); err != nil {
-			log.WithError(err)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		if err = client.CreateFolder(cmd.Context(), args[0], root, args[1]); err != nil {
+			http.HandleError(err, cfg, log)
 		}
 	},
 }
@@ -624,7 +493,8 @@ var chmodFileCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
@@ -638,15 +508,9 @@ var chmodFileCmd = &cobra.Command{
 		}
 
 		root, _ := cmd.Flags().GetString("root")
-		info := map[string]interface{}{"file": args[1], "mode": mode}
-		data, _ := json.Marshal(map[string]interface{}{"root": root, "files": []interface{}{info}})
-		body := bytes.Buffer{}
-		body.Write(data)
-
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("POST", "/api/client/servers/"+args[0]+"/files/chmod", &body)
-		if _, err = ctx.Execute(req); err != nil {
-			log.WithError(err)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		if err = client.ChmodFile(cmd.Context(), args[0], root, args[1], mode); err != nil {
+			http.HandleError(err, cfg, log)
 		}
 	},
 }
@@ -668,38 +532,23 @@ var pullFileCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		dest, _ := cmd.Flags().GetString("dest")
-		name, _ := cmd.Flags().GetString("name")
-		useHeader, _ := cmd.Flags().GetBool("use-header")
-		foreground, _ := cmd.Flags().GetBool("foreground")
+		fields := ptero.PullFileDescriptor{URL: source.String()}
+		fields.Directory, _ = cmd.Flags().GetString("dest")
+		fields.Filename, _ = cmd.Flags().GetString("name")
+		fields.UseHeader, _ = cmd.Flags().GetBool("use-header")
+		fields.Foreground, _ = cmd.Flags().GetBool("foreground")
 
-		info := map[string]interface{}{
-			"url":        source.String(),
-			"use_header": useHeader,
-			"foreground": foreground,
-		}
-		if dest != "" {
-			info["directory"] = dest
-		}
-		if name != "" {
-			info["filename"] = name
-		}
-
-		data, _ := json.Marshal(info)
-		body := bytes.Buffer{}
-		body.Write(data)
-
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("POST", "/api/client/servers/"+args[0]+"/files/pull", &body)
-		if _, err = ctx.Execute(req); err != nil {
-			log.WithError(err)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		if err = client.PullFile(cmd.Context(), args[0], fields); err != nil {
+			http.HandleError(err, cfg, log)
 		}
 	},
 }
@@ -723,33 +572,23 @@ var uploadFilesCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		ctx := http.New(cfg, &cfg.Client, log)
-		urlReq := ctx.Request("GET", "/api/client/servers/"+args[0]+"/files/upload", nil)
-		res, err := ctx.Execute(urlReq)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		location, err := client.GetUploadURL(cmd.Context(), args[0])
 		if err != nil {
-			log.WithError(err)
-			return
-		}
-
-		var model struct {
-			Attributes struct {
-				URL string `json:"url"`
-			} `json:"attributes"`
-		}
-		if err = json.Unmarshal(res, &model); err != nil {
-			log.WithError(err)
+			http.HandleError(err, cfg, log)
 			return
 		}
 
 		if skip {
-			log.Line(model.Attributes.URL)
+			log.Line("%s", location)
 			return
 		}
 
@@ -782,28 +621,16 @@ var uploadFilesCmd = &cobra.Command{
 			return
 		}
 
-		body := bytes.Buffer{}
-		writer := multipart.NewWriter(&body)
-
-		for _, path := range files {
-			file, err := os.Open(path)
-			if err != nil {
-				log.Warn("could not open '%s', skipping", path)
-				continue
-			}
-
-			part, _ := writer.CreateFormFile("files", file.Name())
-			io.Copy(part, file)
-			file.Close()
+		name := strconv.Itoa(len(files)) + " files"
+		if len(files) == 1 {
+			name = filepath.Base(files[0])
 		}
 
-		writer.Close()
-
-		upReq := http.Request("POST", model.Attributes.URL, &body)
-		upReq.Header.Set("Content-Type", writer.FormDataContentType())
-		if _, err = ctx.Execute(upReq); err != nil {
-			log.WithError(err)
-			return
+		progress := util.NewProgress(name, log.Quiet)
+		err = client.UploadFiles(cmd.Context(), location, files, progress)
+		progress.Done()
+		if err != nil {
+			http.HandleError(err, cfg, log)
 		}
 	},
 }
@@ -0,0 +1,248 @@
+package client
+
+import (
+	"strconv"
+
+	"github.com/pteropackages/soar/config"
+	"github.com/pteropackages/soar/http"
+	"github.com/pteropackages/soar/ptero"
+	"github.com/pteropackages/soar/util"
+	"github.com/spf13/cobra"
+)
+
+var listAllocationsCmd = &cobra.Command{
+	Use:     "network:list identifier",
+	Aliases: []string{"network:ls"},
+	Short:   "lists the server allocations",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		allocations, err := client.ListAllocations(cmd.Context(), args[0])
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		buf, err := http.HandleList("server_allocation", allocations, nil, cfg)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		log.LineB(buf)
+	},
+}
+
+var assignAllocationCmd = &cobra.Command{
+	Use:   "network:assign identifier",
+	Short: "assigns a new allocation to the server",
+	Long: "Automatically assigns a new allocation to the server. This requires automatic allocations\n" +
+		"to be enabled on the panel and the server to be below its allocation limit.",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		server, err := client.GetServer(cmd.Context(), args[0])
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		allocations, err := client.ListAllocations(cmd.Context(), args[0])
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		if len(allocations) >= server.FeatureLimits.Allocations {
+			log.Error("the server has reached its allocation limit (%d)", server.FeatureLimits.Allocations)
+			return
+		}
+
+		allocation, err := client.AssignAllocation(cmd.Context(), args[0])
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		buf, err := http.HandleItem("server_allocation", allocation, cfg)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		log.LineB(buf)
+	},
+}
+
+var setAllocationNoteCmd = &cobra.Command{
+	Use:   "network:note identifier allocation note",
+	Short: "sets the note for a server allocation",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier", "allocation", "note"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		allocation, err := strconv.Atoi(args[1])
+		if err != nil {
+			log.Error("invalid allocation id '%s'", args[1])
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		current, err := client.GetAllocation(cmd.Context(), args[0], allocation)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		current, err = client.SetAllocationNote(cmd.Context(), args[0], current.ID, args[2])
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		buf, err := http.HandleItem("server_allocation", current, cfg)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		log.LineB(buf)
+	},
+}
+
+var setPrimaryAllocationCmd = &cobra.Command{
+	Use:   "network:primary identifier allocation",
+	Short: "sets the primary allocation for the server",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier", "allocation"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		allocation, err := strconv.Atoi(args[1])
+		if err != nil {
+			log.Error("invalid allocation id '%s'", args[1])
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		current, err := client.GetAllocation(cmd.Context(), args[0], allocation)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		if current.IsDefault {
+			log.Error("allocation %d is already the primary allocation", current.ID)
+			return
+		}
+
+		current, err = client.SetPrimaryAllocation(cmd.Context(), args[0], current.ID)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		buf, err := http.HandleItem("server_allocation", current, cfg)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		log.LineB(buf)
+	},
+}
+
+var unassignAllocationCmd = &cobra.Command{
+	Use:     "network:unassign identifier allocation",
+	Aliases: []string{"network:rm"},
+	Short:   "unassigns an allocation from the server",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier", "allocation"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		allocation, err := strconv.Atoi(args[1])
+		if err != nil {
+			log.Error("invalid allocation id '%s'", args[1])
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		current, err := client.GetAllocation(cmd.Context(), args[0], allocation)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		if current.IsDefault {
+			log.Error("cannot unassign the primary allocation")
+			return
+		}
+
+		if err = client.UnassignAllocation(cmd.Context(), args[0], current.ID); err != nil {
+			http.HandleError(err, cfg, log)
+		}
+	},
+}
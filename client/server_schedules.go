@@ -0,0 +1,599 @@
+package client
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+
+	"github.com/pteropackages/soar/config"
+	"github.com/pteropackages/soar/http"
+	"github.com/pteropackages/soar/input"
+	"github.com/pteropackages/soar/ptero"
+	"github.com/pteropackages/soar/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var listSchedulesCmd = &cobra.Command{
+	Use:     "schedules:list identifier",
+	Aliases: []string{"schedules:ls"},
+	Short:   "lists the server schedules",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		schedules, err := client.ListSchedules(cmd.Context(), args[0])
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		buf, err := http.HandleList("server_schedule", schedules, nil, cfg)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		log.LineB(buf)
+	},
+}
+
+var getScheduleCmd = &cobra.Command{
+	Use:   "schedules:get identifier id",
+	Short: "gets a server schedule and its tasks",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier", "id"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		schedule, err := strconv.Atoi(args[1])
+		if err != nil {
+			log.Error("invalid schedule id '%s'", args[1])
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		s, err := client.GetSchedule(cmd.Context(), args[0], schedule)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		buf, err := http.HandleItem("server_schedule", s, cfg)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		log.LineB(buf)
+	},
+}
+
+var createScheduleCmd = &cobra.Command{
+	Use:   "schedules:create identifier --data[-file | -json] source",
+	Short: "creates a server schedule",
+	Long:  createScheduleHelp,
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		fields := ptero.NewScheduleDescriptor()
+		err = util.ReadPartialDataFlags(cmd.Flags(), input.Definition{
+			"name":             input.StringNode,
+			"minute":           input.StringNode,
+			"hour":             input.StringNode,
+			"day_of_month":     input.StringNode,
+			"month":            input.StringNode,
+			"day_of_week":      input.StringNode,
+			"is_active":        input.BoolNode,
+			"only_when_online": input.BoolNode,
+		}, &fields)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		s, err := client.CreateSchedule(cmd.Context(), args[0], fields)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		buf, err := http.HandleItem("server_schedule", s, cfg)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		log.LineB(buf)
+	},
+}
+
+var updateScheduleCmd = &cobra.Command{
+	Use:   "schedules:update identifier id --data[-file | -json] source",
+	Short: "updates a server schedule",
+	Long:  updateScheduleHelp,
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier", "id"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		schedule, err := strconv.Atoi(args[1])
+		if err != nil {
+			log.Error("invalid schedule id '%s'", args[1])
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		s, err := client.GetSchedule(cmd.Context(), args[0], schedule)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		fields := s.Descriptor()
+		err = util.ReadPartialDataFlags(cmd.Flags(), input.Definition{
+			"name":             input.StringNode,
+			"minute":           input.StringNode,
+			"hour":             input.StringNode,
+			"day_of_month":     input.StringNode,
+			"month":            input.StringNode,
+			"day_of_week":      input.StringNode,
+			"is_active":        input.BoolNode,
+			"only_when_online": input.BoolNode,
+		}, &fields)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		s, err = client.UpdateSchedule(cmd.Context(), args[0], schedule, fields)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		buf, err := http.HandleItem("server_schedule", s, cfg)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		log.LineB(buf)
+	},
+}
+
+var deleteScheduleCmd = &cobra.Command{
+	Use:     "schedules:delete identifier id",
+	Aliases: []string{"schedules:rm"},
+	Short:   "deletes a server schedule",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier", "id"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		schedule, err := strconv.Atoi(args[1])
+		if err != nil {
+			log.Error("invalid schedule id '%s'", args[1])
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		if err = client.DeleteSchedule(cmd.Context(), args[0], schedule); err != nil {
+			http.HandleError(err, cfg, log)
+		}
+	},
+}
+
+var executeScheduleCmd = &cobra.Command{
+	Use:     "schedules:execute identifier id",
+	Aliases: []string{"schedules:run"},
+	Short:   "executes a server schedule",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier", "id"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		schedule, err := strconv.Atoi(args[1])
+		if err != nil {
+			log.Error("invalid schedule id '%s'", args[1])
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		if err = client.ExecuteSchedule(cmd.Context(), args[0], schedule); err != nil {
+			http.HandleError(err, cfg, log)
+		}
+	},
+}
+
+var createTaskCmd = &cobra.Command{
+	Use:   "schedules:tasks:create identifier id --data[-file | -json] source",
+	Short: "creates a schedule task",
+	Long:  createTaskHelp,
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier", "id"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		schedule, err := strconv.Atoi(args[1])
+		if err != nil {
+			log.Error("invalid schedule id '%s'", args[1])
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		var fields ptero.TaskDescriptor
+		err = util.ReadPartialDataFlags(cmd.Flags(), input.Definition{
+			"action":              input.StringNode,
+			"payload":             input.StringNode,
+			"time_offset":         input.NumberNode,
+			"continue_on_failure": input.BoolNode,
+		}, &fields)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		task, err := client.CreateScheduleTask(cmd.Context(), args[0], schedule, fields)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		buf, err := http.HandleItem("schedule_task", task, cfg)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		log.LineB(buf)
+	},
+}
+
+var updateTaskCmd = &cobra.Command{
+	Use:   "schedules:tasks:update identifier id task --data[-file | -json] source",
+	Short: "updates a schedule task",
+	Long:  updateTaskHelp,
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier", "id", "task"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		schedule, err := strconv.Atoi(args[1])
+		if err != nil {
+			log.Error("invalid schedule id '%s'", args[1])
+			return
+		}
+
+		task, err := strconv.Atoi(args[2])
+		if err != nil {
+			log.Error("invalid task id '%s'", args[2])
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		s, err := client.GetSchedule(cmd.Context(), args[0], schedule)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		var current *ptero.ScheduleTask
+		for _, t := range s.Tasks {
+			if t.ID == task {
+				current = t
+				break
+			}
+		}
+
+		if current == nil {
+			log.Error("task not found")
+			return
+		}
+
+		fields := current.Descriptor()
+		err = util.ReadPartialDataFlags(cmd.Flags(), input.Definition{
+			"action":              input.StringNode,
+			"payload":             input.StringNode,
+			"time_offset":         input.NumberNode,
+			"continue_on_failure": input.BoolNode,
+		}, &fields)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		current, err = client.UpdateScheduleTask(cmd.Context(), args[0], schedule, task, fields)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		buf, err := http.HandleItem("schedule_task", current, cfg)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		log.LineB(buf)
+	},
+}
+
+var deleteTaskCmd = &cobra.Command{
+	Use:     "schedules:tasks:delete identifier id task",
+	Aliases: []string{"schedules:tasks:rm"},
+	Short:   "deletes a schedule task",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier", "id", "task"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		schedule, err := strconv.Atoi(args[1])
+		if err != nil {
+			log.Error("invalid schedule id '%s'", args[1])
+			return
+		}
+
+		task, err := strconv.Atoi(args[2])
+		if err != nil {
+			log.Error("invalid task id '%s'", args[2])
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		if err = client.DeleteScheduleTask(cmd.Context(), args[0], schedule, task); err != nil {
+			http.HandleError(err, cfg, log)
+		}
+	},
+}
+
+var exportSchedulesCmd = &cobra.Command{
+	Use:   "schedules:export identifier [--dest path]",
+	Short: "exports the server schedules as yaml",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		schedules, err := client.ListSchedules(cmd.Context(), args[0])
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		exports := make([]*ptero.ScheduleExport, 0, len(schedules))
+		for _, s := range schedules {
+			exports = append(exports, s.Export())
+		}
+
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err = enc.Encode(exports); err != nil {
+			log.Error("failed to encode schedules:").WithError(err)
+			return
+		}
+
+		dest, _ := cmd.Flags().GetString("dest")
+		if dest == "" {
+			log.LineB(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+			return
+		}
+
+		if err = os.WriteFile(dest, buf.Bytes(), 0o644); err != nil {
+			log.Error("failed to write file:").WithError(err)
+			return
+		}
+
+		log.Ignore().Info("exported %d schedule(s) to %s", len(exports), dest)
+	},
+}
+
+var importSchedulesCmd = &cobra.Command{
+	Use:   "schedules:import identifier file [--replace]",
+	Short: "imports schedules from a yaml file",
+	Long:  importSchedulesHelp,
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier", "file"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		buf, err := util.SafeReadFile(args[1])
+		if err != nil {
+			log.Error("failed to read file:").WithError(err)
+			return
+		}
+
+		var imports []*ptero.ScheduleExport
+		if err = yaml.Unmarshal(buf, &imports); err != nil {
+			log.Error("failed to parse schedules:").WithError(err)
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		for _, s := range imports {
+			if err = s.Validate(); err != nil {
+				log.Error("invalid schedule '%s':", s.Name)
+				http.HandleError(err, cfg, log)
+				return
+			}
+		}
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+
+		// existing schedules are only deleted once their replacement has been
+		// created, so a failed import doesn't leave the server without them
+		replaced := map[string][]int{}
+		if replace, _ := cmd.Flags().GetBool("replace"); replace {
+			existing, err := client.ListSchedules(cmd.Context(), args[0])
+			if err != nil {
+				http.HandleError(err, cfg, log)
+				return
+			}
+
+			for _, s := range existing {
+				replaced[s.Name] = append(replaced[s.Name], s.ID)
+			}
+		}
+
+		for _, s := range imports {
+			created, err := client.CreateSchedule(cmd.Context(), args[0], s.ScheduleDescriptor)
+			if err != nil {
+				log.Error("failed to create schedule '%s':", s.Name)
+				http.HandleError(err, cfg, log)
+				return
+			}
+
+			for _, t := range s.Tasks {
+				if _, err = client.CreateScheduleTask(cmd.Context(), args[0], created.ID, t); err != nil {
+					log.Error("failed to create task for schedule '%s':", s.Name)
+					http.HandleError(err, cfg, log)
+
+					if err = client.DeleteSchedule(cmd.Context(), args[0], created.ID); err != nil {
+						log.Error("failed to delete the incomplete schedule '%s':", s.Name)
+						http.HandleError(err, cfg, log)
+					}
+					return
+				}
+			}
+
+			for _, id := range replaced[s.Name] {
+				if err = client.DeleteSchedule(cmd.Context(), args[0], id); err != nil {
+					log.Error("failed to delete the replaced schedule '%s':", s.Name)
+					http.HandleError(err, cfg, log)
+					return
+				}
+				log.Ignore().Info("deleted the replaced schedule '%s'", s.Name)
+			}
+			delete(replaced, s.Name)
+
+			log.Ignore().Info("imported schedule '%s' with %d task(s)", s.Name, len(s.Tasks))
+		}
+	},
+}
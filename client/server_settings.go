@@ -1,11 +1,12 @@
 package client
 
 import (
-	"bytes"
-	"encoding/json"
+	"fmt"
+	"os"
 
 	"github.com/pteropackages/soar/config"
 	"github.com/pteropackages/soar/http"
+	"github.com/pteropackages/soar/ptero"
 	"github.com/pteropackages/soar/util"
 	"github.com/spf13/cobra"
 )
@@ -21,27 +22,23 @@ var renameServerCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		data, _ := json.Marshal(map[string]string{"name": args[1]})
-		body := bytes.Buffer{}
-		body.Write(data)
-
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("POST", "/api/client/servers/"+args[0]+"/settings/rename", &body)
-		if _, err := ctx.Execute(req); err != nil {
-			log.WithError(err)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		if err := client.RenameServer(cmd.Context(), args[0], args[1]); err != nil {
+			http.HandleError(err, cfg, log)
 		}
 	},
 }
 
 var reinstallServerCmd = &cobra.Command{
-	Use:   "settings:reinstall identifier",
+	Use:   "settings:reinstall identifier [-y] [--wait] [--timeout duration]",
 	Short: "reinstalls a server",
 	Run: func(cmd *cobra.Command, args []string) {
 		log.ApplyFlags(cmd.Flags())
@@ -51,17 +48,34 @@ var reinstallServerCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("POST", "/api/client/servers/"+args[0]+"/settings/reinstall", nil)
-		if _, err := ctx.Execute(req); err != nil {
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		server, err := client.GetServer(cmd.Context(), args[0])
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		prompt := fmt.Sprintf("reinstall server '%s' (uuid %s)? this will run the install script again", server.Name, server.UUID)
+		if err = util.Confirm(cmd.Flags(), prompt, server.Name); err != nil {
 			log.WithError(err)
+			os.Exit(1)
+		}
+
+		if err = client.ReinstallServer(cmd.Context(), args[0]); err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		if wait, _ := cmd.Flags().GetBool("wait"); wait {
+			waitForState(cmd, cfg, client, args[0], "installed")
 		}
 	},
 }
@@ -77,21 +91,17 @@ var setDockerImageCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		data, _ := json.Marshal(map[string]string{"docker_image": args[1]})
-		body := bytes.Buffer{}
-		body.Write(data)
-
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("PUT", "/api/client/servers/"+args[0]+"/settings/docker-image", &body)
-		if _, err := ctx.Execute(req); err != nil {
-			log.WithError(err)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		if err := client.SetDockerImage(cmd.Context(), args[0], args[1]); err != nil {
+			http.HandleError(err, cfg, log)
 		}
 	},
 }
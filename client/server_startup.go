@@ -1,11 +1,9 @@
 package client
 
 import (
-	"bytes"
-	"encoding/json"
-
 	"github.com/pteropackages/soar/config"
 	"github.com/pteropackages/soar/http"
+	"github.com/pteropackages/soar/ptero"
 	"github.com/pteropackages/soar/util"
 	"github.com/spf13/cobra"
 )
@@ -21,22 +19,22 @@ var getStartupCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("GET", "/api/client/servers/"+args[0]+"/startup", nil)
-		res, err := ctx.Execute(req)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		vars, meta, err := client.GetStartup(cmd.Context(), args[0])
 		if err != nil {
-			log.WithError(err)
+			http.HandleError(err, cfg, log)
 			return
 		}
 
-		buf, err := http.HandleDataResponse(res, cfg)
+		buf, err := http.HandleList("egg_variable", vars, meta, cfg)
 		if err != nil {
 			log.WithError(err)
 			return
@@ -57,26 +55,22 @@ var setStartupCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		data, _ := json.Marshal(map[string]string{"key": args[1], "value": args[2]})
-		body := bytes.Buffer{}
-		body.Write(data)
-
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("PUT", "/api/client/servers/"+args[0]+"/startup/variable", &body)
-		res, err := ctx.Execute(req)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		v, err := client.SetStartupVariable(cmd.Context(), args[0], args[1], args[2])
 		if err != nil {
-			log.WithError(err)
+			http.HandleError(err, cfg, log)
 			return
 		}
 
-		buf, err := http.HandleItemResponse(res, cfg)
+		buf, err := http.HandleItem("egg_variable", v, cfg)
 		if err != nil {
 			log.WithError(err)
 			return
@@ -1,11 +1,9 @@
 package client
 
 import (
-	"bytes"
-	"encoding/json"
-
 	"github.com/pteropackages/soar/config"
 	"github.com/pteropackages/soar/http"
+	"github.com/pteropackages/soar/ptero"
 	"github.com/pteropackages/soar/util"
 	"github.com/spf13/cobra"
 )
@@ -21,33 +19,35 @@ var getSubUsersCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		uuid, _ := cmd.Flags().GetString("uuid")
-		path := "/api/client/servers/" + args[0] + "/users"
-		if uuid != "" {
-			path += "/" + uuid
-		}
-
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("GET", path, nil)
-		res, err := ctx.Execute(req)
-		if err != nil {
-			log.WithError(err)
-			return
-		}
-
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
 		var buf []byte
 
-		if uuid != "" {
-			buf, err = http.HandleItemResponse(res, cfg)
+		if uuid, _ := cmd.Flags().GetString("uuid"); uuid != "" {
+			var user *ptero.Subuser
+			user, err = client.GetSubuser(cmd.Context(), args[0], uuid)
+			if err != nil {
+				http.HandleError(err, cfg, log)
+				return
+			}
+
+			buf, err = http.HandleItem("server_subuser", user, cfg)
 		} else {
-			buf, err = http.HandleDataResponse(res, cfg)
+			var users []*ptero.Subuser
+			users, err = client.ListSubusers(cmd.Context(), args[0])
+			if err != nil {
+				http.HandleError(err, cfg, log)
+				return
+			}
+
+			buf, err = http.HandleList("server_subuser", users, nil, cfg)
 		}
 		if err != nil {
 			log.WithError(err)
@@ -74,26 +74,22 @@ var addSubUserCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		data, _ := json.Marshal(map[string]interface{}{"email": args[1], "permissions": args[2:]})
-		body := bytes.Buffer{}
-		body.Write(data)
-
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("POST", "/api/client/servers/"+args[0]+"/users", &body)
-		res, err := ctx.Execute(req)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		user, err := client.AddSubuser(cmd.Context(), args[0], args[1], args[2:])
 		if err != nil {
-			log.WithError(err)
+			http.HandleError(err, cfg, log)
 			return
 		}
 
-		buf, err := http.HandleItemResponse(res, cfg)
+		buf, err := http.HandleItem("server_subuser", user, cfg)
 		if err != nil {
 			log.WithError(err)
 			return
@@ -114,17 +110,17 @@ var removeSubUserCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("DELETE", "/api/client/servers/"+args[0]+"/users/"+args[1], nil)
-		if _, err = ctx.Execute(req); err != nil {
-			log.WithError(err)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		if err = client.RemoveSubuser(cmd.Context(), args[0], args[1]); err != nil {
+			http.HandleError(err, cfg, log)
 		}
 	},
 }
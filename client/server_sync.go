@@ -0,0 +1,326 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pteropackages/soar/config"
+	"github.com/pteropackages/soar/http"
+	"github.com/pteropackages/soar/ptero"
+	"github.com/pteropackages/soar/util"
+	"github.com/spf13/cobra"
+)
+
+type syncEntry struct {
+	size    int64
+	modTime time.Time
+	dir     bool
+	version string
+}
+
+type syncAction struct {
+	op    string
+	path  string
+	entry *syncEntry
+}
+
+var syncFilesCmd = &cobra.Command{
+	Use:   "files:sync identifier local-dir remote-dir [--direction up|down] [--delete] [--dry-run]",
+	Short: "syncs a local directory with a server directory",
+	Long: "Recursively syncs a local directory with a directory on the server. Files are transferred\n" +
+		"if they are missing or their size or modification time differs from the source. With the\n" +
+		"'up' direction (the default) local files are uploaded to the server, and with 'down' server\n" +
+		"files are downloaded. The --delete flag removes files from the destination that don't exist\n" +
+		"in the source, and the --dry-run flag prints the changes without making them.",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier", "local-dir", "remote-dir"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		direction, _ := cmd.Flags().GetString("direction")
+		if direction != "up" && direction != "down" {
+			log.Error("invalid sync direction '%s'", direction)
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		local := filepath.Clean(args[1])
+		remote := path.Clean("/" + args[2])
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+
+		localFiles, err := walkLocal(local)
+		if err != nil {
+			log.Error("failed to read local directory:").WithError(err)
+			return
+		}
+
+		remoteFiles, err := walkRemote(cmd.Context(), client, args[0], remote)
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		del, _ := cmd.Flags().GetBool("delete")
+		var actions []*syncAction
+		if direction == "up" {
+			actions = planSync(localFiles, remoteFiles, del)
+		} else {
+			actions = planSync(remoteFiles, localFiles, del)
+		}
+
+		if len(actions) == 0 {
+			log.Ignore().Info("already in sync")
+			return
+		}
+
+		if dry, _ := cmd.Flags().GetBool("dry-run"); dry {
+			for _, a := range actions {
+				log.Line("%-6s %s", a.op, a.path)
+			}
+			return
+		}
+
+		if remoteFiles == nil && direction == "up" {
+			if err = client.CreateFolder(cmd.Context(), args[0], "/", strings.TrimPrefix(remote, "/")); err != nil {
+				http.HandleError(err, cfg, log)
+				return
+			}
+		}
+
+		if direction == "up" {
+			err = syncUp(cmd.Context(), client, args[0], local, remote, actions)
+		} else {
+			err = syncDown(cmd.Context(), client, args[0], local, remote, actions)
+		}
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		counts := map[string]int{}
+		for _, a := range actions {
+			counts[a.op]++
+		}
+		log.Ignore().Info("synced %d file(s), created %d folder(s), deleted %d file(s)",
+			counts["copy"], counts["mkdir"], counts["delete"])
+	},
+}
+
+func walkLocal(root string) (map[string]*syncEntry, error) {
+	files := map[string]*syncEntry{}
+
+	err := filepath.Walk(root, func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && name == root {
+				return filepath.SkipDir
+			}
+
+			return err
+		}
+
+		if name == root {
+			if !info.IsDir() {
+				return errors.New("local path is not a directory")
+			}
+
+			return nil
+		}
+
+		rel, _ := filepath.Rel(root, name)
+		rel = filepath.ToSlash(rel)
+
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			log.Warn("'%s' is not a regular file, skipping", rel)
+			return nil
+		}
+
+		files[rel] = &syncEntry{size: info.Size(), modTime: info.ModTime(), dir: info.IsDir()}
+		return nil
+	})
+
+	return files, err
+}
+
+func walkRemote(ctx context.Context, client *ptero.Client, id, root string) (map[string]*syncEntry, error) {
+	files := map[string]*syncEntry{}
+
+	err := client.WalkFiles(ctx, id, root, func(dir string, file *ptero.File) error {
+		if file.IsSymlink {
+			return nil
+		}
+
+		rel := strings.TrimPrefix(path.Join(dir, file.Name), strings.TrimSuffix(root, "/")+"/")
+		modTime, _ := time.Parse(time.RFC3339, file.ModifiedAt)
+		files[rel] = &syncEntry{size: file.Size, modTime: modTime, dir: file.IsDir(), version: file.Version()}
+
+		return nil
+	})
+	if err != nil {
+		var e *http.APIError
+		if errors.As(err, &e) && e.IsNotFound() {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return files, nil
+}
+
+func planSync(src, dst map[string]*syncEntry, del bool) []*syncAction {
+	var actions []*syncAction
+
+	for _, name := range sortedKeys(src) {
+		s, d := src[name], dst[name]
+
+		switch {
+		case s.dir:
+			if d == nil {
+				actions = append(actions, &syncAction{op: "mkdir", path: name, entry: s})
+			}
+		case d == nil || d.dir || s.size != d.size || s.modTime.Truncate(time.Second).After(d.modTime):
+			actions = append(actions, &syncAction{op: "copy", path: name, entry: s})
+		}
+	}
+
+	if !del {
+		return actions
+	}
+
+	var deleted []string
+	for _, name := range sortedKeys(dst) {
+		if _, ok := src[name]; ok {
+			continue
+		}
+
+		skip := false
+		for _, dir := range deleted {
+			if strings.HasPrefix(name, dir+"/") {
+				skip = true
+				break
+			}
+		}
+		if skip {
+			continue
+		}
+
+		if dst[name].dir {
+			deleted = append(deleted, name)
+		}
+		actions = append(actions, &syncAction{op: "delete", path: name, entry: dst[name]})
+	}
+
+	return actions
+}
+
+func syncUp(ctx context.Context, client *ptero.Client, id, local, remote string, actions []*syncAction) error {
+	uploads := map[string][]string{}
+	removals := map[string][]string{}
+
+	for _, a := range actions {
+		dir, name := path.Split(a.path)
+
+		switch a.op {
+		case "mkdir":
+			if err := client.CreateFolder(ctx, id, path.Join(remote, dir), name); err != nil {
+				return err
+			}
+			log.Ignore().Info("created folder %s", a.path)
+		case "copy":
+			uploads[dir] = append(uploads[dir], filepath.Join(local, filepath.FromSlash(a.path)))
+		case "delete":
+			removals[dir] = append(removals[dir], name)
+		}
+	}
+
+	for _, dir := range sortedKeys(uploads) {
+		location, err := client.GetUploadURL(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err = client.UploadFilesTo(ctx, location, path.Join(remote, dir), uploads[dir], nil); err != nil {
+			return err
+		}
+		log.Ignore().Info("uploaded %d file(s) to %s", len(uploads[dir]), path.Join(remote, dir))
+	}
+
+	for _, dir := range sortedKeys(removals) {
+		if err := client.DeleteFiles(ctx, id, path.Join(remote, dir), removals[dir]); err != nil {
+			return err
+		}
+		log.Ignore().Info("deleted %d file(s) from %s", len(removals[dir]), path.Join(remote, dir))
+	}
+
+	return nil
+}
+
+func syncDown(ctx context.Context, client *ptero.Client, id, local, remote string, actions []*syncAction) error {
+	for _, a := range actions {
+		dest := filepath.Join(local, filepath.FromSlash(a.path))
+
+		switch a.op {
+		case "mkdir":
+			if err := os.MkdirAll(dest, 0o755); err != nil {
+				return err
+			}
+		case "copy":
+			location, err := client.GetFileDownloadURL(ctx, id, path.Join(remote, a.path))
+			if err != nil {
+				return err
+			}
+
+			if err = os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+				return err
+			}
+
+			if err = client.DownloadFile(ctx, location, dest, a.entry.version, nil); err != nil {
+				return err
+			}
+
+			os.Chtimes(dest, a.entry.modTime, a.entry.modTime)
+			log.Ignore().Info("downloaded %s", a.path)
+		case "delete":
+			if err := os.RemoveAll(dest); err != nil {
+				return err
+			}
+			log.Ignore().Info("deleted %s", a.path)
+		}
+	}
+
+	return nil
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+
+	switch v := m.(type) {
+	case map[string]*syncEntry:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string][]string:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
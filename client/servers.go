@@ -1,11 +1,15 @@
 package client
 
 import (
-	"bytes"
-	"encoding/json"
+	"bufio"
+	"context"
+	"os"
+	"os/signal"
+	"time"
 
 	"github.com/pteropackages/soar/config"
 	"github.com/pteropackages/soar/http"
+	"github.com/pteropackages/soar/ptero"
 	"github.com/pteropackages/soar/util"
 	"github.com/spf13/cobra"
 )
@@ -17,32 +21,36 @@ var getServersCmd = &cobra.Command{
 		log.ApplyFlags(cmd.Flags())
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		id, _ := cmd.Flags().GetString("id")
-		path := "/api/client"
-		if id != "" {
-			path += "/servers/" + id
-		}
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		var buf []byte
 
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("GET", path, nil)
-		res, err := ctx.Execute(req)
-		if err != nil {
-			log.WithError(err)
-			return
-		}
+		if id, _ := cmd.Flags().GetString("id"); id != "" {
+			var server *ptero.ClientServer
+			server, err = client.GetServer(cmd.Context(), id)
+			if err != nil {
+				http.HandleError(err, cfg, log)
+				return
+			}
 
-		var buf []byte
-		if id != "" {
-			buf, err = http.HandleItemResponse(res, cfg)
+			buf, err = http.HandleItem("server", server, cfg)
 		} else {
-			buf, err = http.HandleDataResponse(res, cfg)
+			var servers []*ptero.ClientServer
+			var meta *ptero.Meta
+			servers, meta, err = client.ListServers(cmd.Context(), util.ParseListOptions(cmd.Flags()))
+			if err != nil {
+				http.HandleError(err, cfg, log)
+				return
+			}
+
+			buf, err = http.HandleList("server", servers, meta, cfg)
 		}
 		if err != nil {
 			log.WithError(err)
@@ -65,46 +73,27 @@ var getServerWSCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("GET", "/api/client/servers/"+args[0]+"/websocket", nil)
-		res, err := ctx.Execute(req)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		auth, err := client.GetServerWebSocket(cmd.Context(), args[0])
 		if err != nil {
-			log.WithError(err)
-			return
-		}
-
-		var model struct {
-			Data struct {
-				Token  string `json:"token"`
-				Socket string `json:"socket"`
-			} `json:"data"`
-		}
-		if err = json.Unmarshal(res, &model); err != nil {
-			log.WithError(err)
+			http.HandleError(err, cfg, log)
 			return
 		}
 
 		var buf []byte
 
 		if cfg.Http.ParseBody {
-			if cfg.Http.ParseIndent {
-				buf, err = json.MarshalIndent(model.Data, "", "  ")
-			} else {
-				buf, err = json.Marshal(model.Data)
-			}
+			buf, err = http.HandleJSON(auth, cfg)
 		} else {
-			if cfg.Http.ParseIndent {
-				buf, err = json.MarshalIndent(model, "", "  ")
-			} else {
-				buf, err = json.Marshal(model)
-			}
+			buf, err = http.HandleJSON(map[string]interface{}{"data": auth}, cfg)
 		}
 		if err != nil {
 			log.WithError(err)
@@ -127,22 +116,22 @@ var getServerResourcesCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("GET", "/api/client/servers/"+args[0]+"/resources", nil)
-		res, err := ctx.Execute(req)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		res, err := client.GetServerResources(cmd.Context(), args[0])
 		if err != nil {
-			log.WithError(err)
+			http.HandleError(err, cfg, log)
 			return
 		}
 
-		buf, err := http.HandleItemResponse(res, cfg)
+		buf, err := http.HandleItem("stats", res, cfg)
 		if err != nil {
 			log.WithError(err)
 			return
@@ -152,6 +141,108 @@ var getServerResourcesCmd = &cobra.Command{
 	},
 }
 
+var serverTopCmd = &cobra.Command{
+	Use:   "servers:top [identifiers...] [--interval duration] [--once]",
+	Short: "monitors server resource usage",
+	Long: "Displays a refreshing table of resource usage for the specified servers, or all\n" +
+		"servers available to the account if none are specified. Use the --once flag to\n" +
+		"print the usage a single time (e.g. with --output csv).",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		if cfg.Http.Output == "" {
+			cfg.Http.Output = "table"
+		}
+
+		once, _ := cmd.Flags().GetBool("once")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		if interval < time.Second {
+			log.Error("the interval must be at least 1s")
+			return
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+
+		// request logs would break up the table between refreshes
+		log.Quiet = true
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		servers := make([]*ptero.ServerResources, 0, len(args))
+
+		if len(args) == 0 {
+			list, _, err := client.ListServers(ctx, &ptero.ListOptions{All: true})
+			if err != nil {
+				http.HandleError(err, cfg, log)
+				return
+			}
+
+			for _, s := range list {
+				servers = append(servers, &ptero.ServerResources{Identifier: s.Identifier, Name: s.Name})
+			}
+		} else {
+			for _, id := range args {
+				servers = append(servers, &ptero.ServerResources{Identifier: id})
+			}
+		}
+
+		if len(servers) == 0 {
+			log.Error("no servers found to monitor")
+			return
+		}
+
+		refresh := !once && util.IsTerminal(os.Stdout)
+
+		for {
+			for _, s := range servers {
+				res, err := client.GetServerResources(ctx, s.Identifier)
+				if err != nil {
+					if ctx.Err() != nil {
+						return
+					}
+
+					log.Debug("failed to get resources for %s: %v", s.Identifier, err)
+					s.Resources = ptero.Resources{CurrentState: "unknown"}
+					continue
+				}
+
+				s.Resources = *res
+			}
+
+			buf, err := http.HandleList("server_stats", servers, nil, cfg)
+			if err != nil {
+				log.WithError(err)
+				return
+			}
+
+			if refresh {
+				log.Line("\x1b[H\x1b[2J%s", buf)
+			} else {
+				log.LineB(buf)
+			}
+
+			if once {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+	},
+}
+
 var getServerActivityCmd = &cobra.Command{
 	Use:   "servers:activity identifier",
 	Short: "gets the server activity logs",
@@ -163,22 +254,22 @@ var getServerActivityCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("GET", "/api/client/servers/"+args[0]+"/activity", nil)
-		res, err := ctx.Execute(req)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		activity, meta, err := client.ListServerActivity(cmd.Context(), args[0], util.ParseListOptions(cmd.Flags()))
 		if err != nil {
-			log.WithError(err)
+			http.HandleError(err, cfg, log)
 			return
 		}
 
-		buf, err := http.HandleDataResponse(res, cfg)
+		buf, err := http.HandleList("activity_log", activity, meta, cfg)
 		if err != nil {
 			log.WithError(err)
 			return
@@ -200,62 +291,244 @@ var sendServerCommandCmd = &cobra.Command{
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		data, _ := json.Marshal(map[string]string{"command": args[1]})
-		body := bytes.Buffer{}
-		body.Write(data)
-
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("POST", "/api/client/servers/"+args[0]+"/command", &body)
-		if _, err = ctx.Execute(req); err != nil {
-			log.WithError(err)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		if err = client.SendServerCommand(cmd.Context(), args[0], args[1]); err != nil {
+			http.HandleError(err, cfg, log)
 		}
 	},
 }
 
 var setServerPowerStateCmd = &cobra.Command{
-	Use:     "servers:power identifier state",
+	Use:     "servers:power identifier state [--wait] [--timeout duration]\n\t[--selector key=value,...] [--from-file path] [--parallel n]",
 	Aliases: []string{"servers:state", "servers:status", "servers:toggle"},
 	Short:   "sets the server power state",
+	Long: "Sets the power state of a server (start, stop, restart or kill).\n\n" +
+		"To run on multiple servers, use '--selector key=value,...' to match them by their fields (the JSON\n" +
+		"field names, with dots for nested fields, e.g. \"node=node1,docker_image=...\"), or '--from-file path'\n" +
+		"to read one identifier per line from a file ('-' or an identifier argument of '-' reads stdin). The\n" +
+		"state is then the only other argument. The '--parallel n' flag runs on up to n servers at once. The\n" +
+		"result for each server is printed, and the exit code is non-zero if any of them failed.",
 	Run: func(cmd *cobra.Command, args []string) {
 		log.ApplyFlags(cmd.Flags())
-		if err := util.RequireArgs(args, []string{"identifier", "state"}); err != nil {
+		bulk := util.IsBulk(cmd.Flags(), args)
+		if bulk {
+			if len(args) == 0 {
+				log.Error("missing argument 'state'")
+				return
+			}
+		} else if err := util.RequireArgs(args, []string{"identifier", "state"}); err != nil {
 			log.WithError(err)
 			return
 		}
 
-		switch args[1] {
+		power := args[len(args)-1]
+		switch power {
 		case "start":
 		case "stop":
 		case "restart":
 		case "kill":
 		default:
-			log.Error("invalid power state '%s'", args[1])
+			log.Error("invalid power state '%s'", power)
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		wait, _ := cmd.Flags().GetBool("wait")
+		state := "running"
+		switch power {
+		case "stop", "kill":
+			state = "offline"
+		case "restart":
+			state = "restarted"
+		}
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		if bulk {
+			ids, err := serverTargets(cmd, client, args[:len(args)-1])
+			if err != nil {
+				http.HandleError(err, cfg, log)
+				return
+			}
+
+			timeout, _ := cmd.Flags().GetDuration("timeout")
+			runBulk(cmd, ids, func(ctx context.Context, id string) error {
+				if err := client.SetServerPowerState(ctx, id, power); err != nil || !wait {
+					return err
+				}
+
+				ctx, cancel := context.WithTimeout(ctx, timeout)
+				defer cancel()
+				return waitFor(ctx, client, id, state)
+			})
+			return
+		}
+
+		if err = client.SetServerPowerState(cmd.Context(), args[0], power); err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+
+		if wait {
+			waitForState(cmd, cfg, client, args[0], state)
+		}
+	},
+}
+
+var waitServerCmd = &cobra.Command{
+	Use:   "servers:wait identifier --state state [--timeout duration]",
+	Short: "waits for a server to reach a state",
+	Long: "Waits for a server to reach the specified state (running, offline or installed),\n" +
+		"exiting with a non-zero status code if the timeout is reached first.",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier"}); err != nil {
+			log.WithError(err)
+			return
+		}
+
+		state, _ := cmd.Flags().GetString("state")
+		switch state {
+		case "running":
+		case "offline":
+		case "installed":
+		default:
+			log.Error("invalid server state '%s'", state)
 			return
 		}
 
 		global, _ := cmd.Flags().GetBool("global")
-		cfg, err := config.Get(global)
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
 		if err != nil {
 			config.HandleError(err, log)
 			return
 		}
 		cfg.ApplyFlags(cmd.Flags())
 
-		data, _ := json.Marshal(map[string]string{"signal": args[1]})
-		body := bytes.Buffer{}
-		body.Write(data)
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		waitForState(cmd, cfg, client, args[0], state)
+	},
+}
+
+func waitForState(cmd *cobra.Command, cfg *config.Config, client *ptero.Client, id, state string) {
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+	defer cancel()
+
+	log.Ignore().Info("waiting for the server to be %s", state)
+	if err := waitFor(ctx, client, id, state); err != nil {
+		http.HandleError(err, cfg, log)
+		os.Exit(1)
+	}
+}
 
-		ctx := http.New(cfg, &cfg.Client, log)
-		req := ctx.Request("POST", "/api/client/servers/"+args[0]+"/power", &body)
-		if _, err = ctx.Execute(req); err != nil {
+// waitFor waits for the server to reach the state, where "restarted" means the
+// server has stopped and then started running again.
+func waitFor(ctx context.Context, client *ptero.Client, id, state string) error {
+	if state == "restarted" {
+		return client.WaitForRestart(ctx, id)
+	}
+
+	return client.WaitForState(ctx, id, state)
+}
+
+var serverConsoleCmd = &cobra.Command{
+	Use:     "servers:console identifier",
+	Aliases: []string{"console"},
+	Short:   "connects to the server console",
+	Long: "Connects to the server websocket and streams the console output, status and resource\n" +
+		"stats. Each line written to stdin is sent to the server as a command.",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+		if err := util.RequireArgs(args, []string{"identifier"}); err != nil {
 			log.WithError(err)
+			return
+		}
+
+		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
+		cfg, err := config.Get(global, profile)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+		cfg.ApplyFlags(cmd.Flags())
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+
+		client := ptero.NewClient(http.New(cfg, &cfg.Client, log))
+		console, err := client.ConnectConsole(ctx, args[0])
+		if err != nil {
+			http.HandleError(err, cfg, log)
+			return
+		}
+		defer console.Close()
+
+		go func() {
+			scanner := bufio.NewScanner(os.Stdin)
+			for scanner.Scan() {
+				if err := console.SendCommand(scanner.Text()); err != nil {
+					log.Error("failed to send command:").WithError(err)
+				}
+			}
+		}()
+
+		connected := false
+		for {
+			event, err := console.Read(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Error("console connection closed:").WithError(err)
+				}
+				return
+			}
+
+			switch event.Event {
+			case "auth success":
+				if !connected {
+					log.Ignore().Info("connected to server console")
+					console.Send("send logs")
+					connected = true
+				}
+			case "console output", "install output":
+				for _, line := range event.Args {
+					log.Line("%s", line)
+				}
+			case "status":
+				if len(event.Args) != 0 {
+					log.Ignore().Info("server status: %s", event.Args[0])
+				}
+			case "stats":
+				stats, err := event.Stats()
+				if err != nil {
+					log.Debug("failed to decode stats: %v", err)
+					continue
+				}
+
+				log.Ignore().Info("cpu: %.2f%%, memory: %s, disk: %s, state: %s",
+					stats.CPUAbsolute, http.FormatBytes(float64(stats.MemoryBytes)), http.FormatBytes(float64(stats.DiskBytes)), stats.State)
+			case "daemon message", "daemon error", "jwt error":
+				for _, line := range event.Args {
+					log.Warn("%s", line)
+				}
+			}
 		}
 	},
 }
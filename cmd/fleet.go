@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/pteropackages/soar/config"
+	"github.com/pteropackages/soar/fleet"
+	"github.com/pteropackages/soar/http"
+	"github.com/pteropackages/soar/ptero"
+	"github.com/pteropackages/soar/util"
+	"github.com/spf13/cobra"
+)
+
+var fleetHelp = "Manifests are YAML files with locations, nodes, users and servers sections. Resources are matched\n" +
+	"to the panel by the location short code, node name, username, and server external_id (or name if no\n" +
+	"external_id is set). Fields use the same names as the API, and fields that are omitted are kept as\n" +
+	"they are on the panel. References to other resources use their names instead of IDs:\n\n" +
+	"  locations:\n" +
+	"    - short: us-east\n" +
+	"      long: US East\n" +
+	"  nodes:\n" +
+	"    - name: node1\n" +
+	"      location: us-east\n" +
+	"      fqdn: node1.example.com\n" +
+	"      memory: 16384\n" +
+	"      disk: 100000\n" +
+	"      allocations:\n" +
+	"        - ip: 10.0.0.1\n" +
+	"          ports: [\"25565-25570\"]\n" +
+	"  users:\n" +
+	"    - username: alice\n" +
+	"      email: alice@example.com\n" +
+	"      first_name: Alice\n" +
+	"      last_name: Smith\n" +
+	"  servers:\n" +
+	"    - name: survival\n" +
+	"      external_id: survival\n" +
+	"      user: alice\n" +
+	"      node: node1\n" +
+	"      allocation: 10.0.0.1:25565\n" +
+	"      nest: 1\n" +
+	"      egg: 5\n" +
+	"      environment:\n" +
+	"        SERVER_JARFILE: server.jar\n" +
+	"      limits:\n" +
+	"        memory: 4096\n" +
+	"        disk: 10000\n\n" +
+	"Resources that are not in the manifest are only deleted with the --prune flag, and only for the\n" +
+	"sections that are in the manifest. Allocations that are assigned to a server are never deleted."
+
+var planCmd = &cobra.Command{
+	Use:   "plan -f file [--prune]",
+	Short: "shows the changes needed to apply a fleet manifest",
+	Long:  "Compares a fleet manifest with the panel and shows the changes that 'soar apply' would make.\n\n" + fleetHelp,
+	Run: func(cmd *cobra.Command, _ []string) {
+		log.ApplyFlags(cmd.Flags())
+
+		plan, _ := buildPlan(cmd)
+		if plan == nil {
+			return
+		}
+
+		log.Line("%s", plan.Format())
+	},
+}
+
+var applyCmd = &cobra.Command{
+	Use:   "apply -f file [--prune] [-y]",
+	Short: "applies a fleet manifest to the panel",
+	Long:  "Creates, updates and deletes panel resources to match a fleet manifest. The plan is printed before\nany changes are made, and must be confirmed unless the --yes flag is set.\n\n" + fleetHelp,
+	Run: func(cmd *cobra.Command, _ []string) {
+		log.ApplyFlags(cmd.Flags())
+
+		plan, cfg := buildPlan(cmd)
+		if plan == nil {
+			return
+		}
+
+		log.Line("%s", plan.Format())
+		if len(plan.Changes) == 0 {
+			return
+		}
+		log.Line("")
+
+		prompt := fmt.Sprintf("apply %d change(s) to the panel?", len(plan.Changes))
+		if n := plan.Count("delete"); n != 0 {
+			prompt = fmt.Sprintf("apply %d change(s) to the panel? this will delete %d resource(s)", len(plan.Changes), n)
+		}
+		if err := util.Confirm(cmd.Flags(), prompt, "yes"); err != nil {
+			log.WithError(err)
+			os.Exit(1)
+		}
+
+		verbs := map[string]string{"create": "creating", "update": "updating", "delete": "deleting"}
+		for _, c := range plan.Changes {
+			log.Line("%s %s %s", verbs[c.Action], c.Kind, c.Name)
+
+			err := c.Apply(cmd.Context())
+			if err == nil || errors.Is(err, http.ErrDryRun) {
+				continue
+			}
+
+			// changes that refer to resources created earlier in the plan can't be
+			// shown because nothing was created
+			if cfg.Http.DryRun {
+				log.Warn("can't show the requests for %s %s: %v", c.Kind, c.Name, err)
+				continue
+			}
+
+			log.Error("failed to %s %s %s:", c.Action, c.Kind, c.Name)
+			http.HandleError(err, cfg, log)
+			os.Exit(1)
+		}
+
+		if cfg.Http.DryRun {
+			log.Line("\nDry run complete, no changes were made.")
+			return
+		}
+
+		log.Line("\nApply complete: %d created, %d updated, %d deleted.",
+			plan.Count("create"), plan.Count("update"), plan.Count("delete"))
+	},
+}
+
+func buildPlan(cmd *cobra.Command) (*fleet.Plan, *config.Config) {
+	file, _ := cmd.Flags().GetString("file")
+	if file == "" {
+		log.Error("a manifest file must be specified with the --file flag")
+		return nil, nil
+	}
+
+	manifest, err := fleet.Load(file)
+	if err != nil {
+		log.WithError(err)
+		return nil, nil
+	}
+
+	global, _ := cmd.Flags().GetBool("global")
+	profile, _ := cmd.Flags().GetString("profile")
+	cfg, err := config.Get(global, profile)
+	if err != nil {
+		config.HandleError(err, log)
+		return nil, nil
+	}
+	cfg.ApplyFlags(cmd.Flags())
+
+	// request logs would break up the plan output
+	log.Quiet = true
+
+	prune, _ := cmd.Flags().GetBool("prune")
+	app := ptero.NewApplication(http.New(cfg, &cfg.Application, log))
+	plan, err := fleet.Build(cmd.Context(), app, manifest, prune)
+	if err != nil {
+		http.HandleError(err, cfg, log)
+		return nil, nil
+	}
+
+	for _, w := range plan.Warnings {
+		log.Warn("%s", w)
+	}
+
+	return plan, cfg
+}
@@ -1,10 +1,9 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
-	"path/filepath"
 	"runtime/debug"
+	"sort"
 
 	"github.com/pteropackages/soar/app"
 	"github.com/pteropackages/soar/client"
@@ -12,7 +11,6 @@ import (
 	"github.com/pteropackages/soar/logger"
 	"github.com/pteropackages/soar/util"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 var log = logger.New()
@@ -46,12 +44,12 @@ var initConfigCmd = &cobra.Command{
 			return
 		}
 
-		log.Line(path)
+		log.Line("%s", path)
 	},
 }
 
 var copyConfigCmd = &cobra.Command{
-	Use:   "copy scope",
+	Use:   "copy scope [--profile name]",
 	Short: "copies a global or local config to the corresponding destination",
 	Run: func(cmd *cobra.Command, args []string) {
 		log.ApplyFlags(cmd.Flags())
@@ -73,49 +71,138 @@ var copyConfigCmd = &cobra.Command{
 			return
 		}
 
-		var path string
+		source, path := config.LocalPath(), config.LocalPath()
+		root, err := config.GlobalPath()
+		if err != nil {
+			log.Error("failed to get global config:").WithError(err)
+			return
+		}
 
 		if global {
-			root, err := os.UserConfigDir()
-			if err != nil {
-				log.Error("failed to get global config:").WithError(err)
+			source = root
+		} else {
+			path = root
+		}
+
+		cfg, err := config.Load(source)
+		if err != nil {
+			log.WithError(err)
+			return
+		}
+
+		if name, _ := cmd.Flags().GetString("profile"); name != "" {
+			profile, ok := cfg.Profiles[name]
+			if !ok {
+				log.Error("profile '%s' not found", name)
+				return
 			}
 
-			if _, err = os.Stat(root); err != nil {
-				if os.IsNotExist(err) {
-					log.Error(fmt.Sprintf("user config directory not found (path: %s)", err))
+			dest, err := config.Load(path)
+			if err != nil {
+				if _, serr := os.Stat(path); serr == nil {
+					log.WithError(err)
 					return
 				}
 
-				log.WithError(err)
-				return
+				dest = &config.Config{Http: cfg.Http, Logs: cfg.Logs, Profile: name}
 			}
-			path = filepath.Join(root, ".soar", "config.yml")
-		} else {
-			root, _ := os.Getwd()
-			path = filepath.Join(root, ".soar.yml")
+
+			if dest.Profiles == nil {
+				dest.Profiles = map[string]*config.Profile{}
+			}
+			dest.Profiles[name] = profile
+			cfg = dest
 		}
 
-		cfg, err := config.GetStatic(global)
-		if err != nil {
+		if err = config.Save(path, cfg); err != nil {
 			log.WithError(err)
 			return
 		}
 
-		file, err := os.Create(path)
-		if err != nil {
+		log.Line("%s", path)
+	},
+}
+
+var useConfigCmd = &cobra.Command{
+	Use:   "use profile [-g | --global]",
+	Short: "sets the default profile for the config",
+	Long:  "Sets the default profile for the config. Use 'default' to switch back to the top-level credentials.",
+	Run: func(cmd *cobra.Command, args []string) {
+		log.ApplyFlags(cmd.Flags())
+
+		if err := util.RequireArgs(args, []string{"profile"}); err != nil {
 			log.WithError(err)
 			return
 		}
-		defer file.Close()
 
-		buf, _ := yaml.Marshal(cfg)
-		file.Write(buf)
+		global, _ := cmd.Flags().GetBool("global")
+		path, err := config.Path(global)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+
+		cfg, err := config.Load(path)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+
+		if _, ok := cfg.Profiles[args[0]]; ok {
+			cfg.Profile = args[0]
+		} else if args[0] == "default" {
+			cfg.Profile = ""
+		} else {
+			log.Error("profile '%s' not found", args[0])
+			return
+		}
+
+		if err = config.Save(path, cfg); err != nil {
+			log.Error("failed to save config:").WithError(err)
+			return
+		}
+
+		log.Ignore().Info("switched to profile '%s'", args[0])
+	},
+}
+
+var listProfilesCmd = &cobra.Command{
+	Use:   "profiles [-g | --global]",
+	Short: "lists the profiles in the config",
+	Run: func(cmd *cobra.Command, _ []string) {
+		log.ApplyFlags(cmd.Flags())
+
+		global, _ := cmd.Flags().GetBool("global")
+		cfg, err := config.GetStatic(global)
+		if err != nil {
+			config.HandleError(err, log)
+			return
+		}
+
+		names := make([]string, 0, len(cfg.Profiles))
+		for name := range cfg.Profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		if cfg.Profile == "" {
+			log.Line("* default")
+		} else {
+			log.Line("  default")
+		}
+
+		for _, name := range names {
+			if name == cfg.Profile {
+				log.Line("* %s", name)
+			} else {
+				log.Line("  %s", name)
+			}
+		}
 	},
 }
 
 var configCmd = &cobra.Command{
-	Use:   "config [init] [-g | --global] [-v | --validate]",
+	Use:   "config [init|copy|use|profiles] [-g | --global] [-v | --validate] [--profile name]",
 	Short: "manages the soar config",
 	Long:  "Manages the soar config for HTTP and logging",
 	Run: func(cmd *cobra.Command, _ []string) {
@@ -126,9 +213,10 @@ var configCmd = &cobra.Command{
 
 		validate, _ := cmd.Flags().GetBool("validate")
 		global, _ := cmd.Flags().GetBool("global")
+		profile, _ := cmd.Flags().GetString("profile")
 
 		if validate {
-			cfg, err = config.Get(global)
+			cfg, err = config.Get(global, profile)
 		} else {
 			cfg, err = config.GetStatic(global)
 		}
@@ -147,13 +235,33 @@ func init() {
 	initConfigCmd.Flags().Bool("no-color", false, "disable ansi color codes")
 
 	configCmd.AddCommand(initConfigCmd)
+	copyConfigCmd.Flags().String("profile", "", "only copy the specified profile")
+	copyConfigCmd.Flags().Bool("no-color", false, "disable ansi color codes")
+	useConfigCmd.Flags().BoolP("global", "g", false, "use the global config")
+	useConfigCmd.Flags().Bool("no-color", false, "disable ansi color codes")
+	listProfilesCmd.Flags().BoolP("global", "g", false, "use the global config")
+	listProfilesCmd.Flags().Bool("no-color", false, "disable ansi color codes")
+
 	configCmd.AddCommand(copyConfigCmd)
+	configCmd.AddCommand(useConfigCmd)
+	configCmd.AddCommand(listProfilesCmd)
 	configCmd.Flags().BoolP("global", "g", false, "use the global config")
 	configCmd.Flags().Bool("no-color", false, "disable ansi color codes")
+	configCmd.Flags().String("profile", "", "the profile to validate with")
 	configCmd.Flags().BoolP("validate", "v", false, "validate the config")
 
+	util.ApplyDefaultFlags(planCmd)
+	util.ApplyDefaultFlags(applyCmd)
+	util.ApplyConfirmFlags(applyCmd)
+	planCmd.Flags().StringP("file", "f", "", "the path to the fleet manifest")
+	planCmd.Flags().Bool("prune", false, "delete resources that aren't in the manifest")
+	applyCmd.Flags().StringP("file", "f", "", "the path to the fleet manifest")
+	applyCmd.Flags().Bool("prune", false, "delete resources that aren't in the manifest")
+
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(planCmd)
+	rootCmd.AddCommand(applyCmd)
 	rootCmd.AddCommand(app.GroupCommands())
 	rootCmd.AddCommand(client.GroupCommands())
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
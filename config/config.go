@@ -14,16 +14,24 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+var ErrNotFound = errors.New("file path does not exist")
+
 type Auth struct {
 	URL string `validate:"required,url" yaml:"url"`
 	Key string `validate:"required" yaml:"key"`
 }
 
 type HttpConfig struct {
-	ParseBody      bool `yaml:"parse_body"`
-	ParseErrors    bool `yaml:"parse_errors"`
-	ParseIndent    bool `yaml:"parse_indent"`
-	RetryRateLimit bool `yaml:"retry_rate_limit"`
+	ParseBody      bool   `yaml:"parse_body"`
+	ParseErrors    bool   `yaml:"parse_errors"`
+	ParseIndent    bool   `yaml:"parse_indent"`
+	RetryRateLimit bool   `yaml:"retry_rate_limit"`
+	MaxRetries     int    `yaml:"max_retries"`
+	RateLimit      int    `yaml:"rate_limit"`
+	Output         string `yaml:"output"`
+	// DryRun can only be enabled with the --dry-run flag, so that it is never left
+	// on by a config file.
+	DryRun bool `yaml:"-"`
 }
 
 type LogConfig struct {
@@ -32,11 +40,32 @@ type LogConfig struct {
 	IgnoreWarnings bool `yaml:"ignore_warnings"`
 }
 
+// Apply applies the log options to the logger. The options can only disable colors
+// and enable debug logs or warnings, so that the command flags are still honoured.
+func (c *LogConfig) Apply(log *logger.Logger) {
+	log.UseColor = log.UseColor && c.UseColor
+	log.UseDebug = log.UseDebug || c.UseDebug
+	log.IgnoreWarnings = log.IgnoreWarnings || c.IgnoreWarnings
+}
+
+type Profile struct {
+	Application Auth `yaml:"application"`
+	Client      Auth `yaml:"client"`
+}
+
 type Config struct {
-	Application Auth       `validate:"required" yaml:"application"`
-	Client      Auth       `validate:"required" yaml:"client"`
-	Http        HttpConfig `validate:"required" yaml:"http"`
-	Logs        LogConfig  `yaml:"logs"`
+	Application Auth                `validate:"required" yaml:"application"`
+	Client      Auth                `validate:"required" yaml:"client"`
+	Http        HttpConfig          `validate:"required" yaml:"http"`
+	Logs        LogConfig           `yaml:"logs"`
+	Profile     string              `yaml:"profile,omitempty"`
+	Profiles    map[string]*Profile `yaml:"profiles,omitempty"`
+}
+
+// newConfig returns a config with the default options for fields that are not
+// set in the config file.
+func newConfig() *Config {
+	return &Config{Logs: LogConfig{UseColor: true}}
 }
 
 func (c *Config) Format() string {
@@ -67,6 +96,14 @@ func (c *Config) ApplyFlags(flags *pflag.FlagSet) {
 		c.Http.ParseErrors = false
 	}
 
+	if output, _ := flags.GetString("output"); output != "" {
+		c.Http.Output = output
+	}
+
+	if ok, _ := flags.GetBool("dry-run"); ok {
+		c.Http.DryRun = true
+	}
+
 	if ok, _ := flags.GetBool("parse-indent"); ok {
 		c.Http.ParseIndent = true
 	}
@@ -75,39 +112,75 @@ func (c *Config) ApplyFlags(flags *pflag.FlagSet) {
 	}
 }
 
-func GetStatic(global bool) (*Config, error) {
-	var path string
+func (c *Config) UseProfile(name string) error {
+	if name == "" {
+		name = c.Profile
+	}
+	if name == "" {
+		return nil
+	}
 
-	if !global {
-		root, _ := os.Getwd()
-		path = filepath.Join(root, ".soar.yml")
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return fmt.Errorf("profile '%s' not found", name)
+	}
 
-		if _, err := os.Stat(path); err != nil {
-			path = ""
-		}
+	if profile.Application.URL != "" {
+		c.Application.URL = profile.Application.URL
 	}
+	if profile.Application.Key != "" {
+		c.Application.Key = profile.Application.Key
+	}
+	if profile.Client.URL != "" {
+		c.Client.URL = profile.Client.URL
+	}
+	if profile.Client.Key != "" {
+		c.Client.Key = profile.Client.Key
+	}
+	c.Profile = name
 
-	if path == "" {
-		root, err := os.UserConfigDir()
-		if err != nil {
-			return nil, err
-		}
+	return nil
+}
 
-		if _, err = os.Stat(root); err != nil {
-			if os.IsNotExist(err) {
-				return nil, fmt.Errorf("user config directory not found (path: %s)", root)
-			}
+func LocalPath() string {
+	root, _ := os.Getwd()
+	return filepath.Join(root, ".soar.yml")
+}
 
-			return nil, err
+func GlobalPath() (string, error) {
+	root, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(root, ".soar", "config.yml"), nil
+}
+
+func Path(global bool) (string, error) {
+	if !global {
+		path := LocalPath()
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
 		}
+	}
+
+	return GlobalPath()
+}
 
-		path = filepath.Join(root, ".soar", "config.yml")
+func GetStatic(global bool) (*Config, error) {
+	path, err := Path(global)
+	if err != nil {
+		return nil, err
 	}
 
+	return Load(path)
+}
+
+func Load(path string) (*Config, error) {
 	info, err := os.Stat(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, errors.New("file path does not exist")
+			return nil, ErrNotFound
 		}
 
 		return nil, err
@@ -126,18 +199,57 @@ func GetStatic(global bool) (*Config, error) {
 		return nil, err
 	}
 
-	var cfg *Config
-	if err = yaml.Unmarshal(buf, &cfg); err != nil {
+	cfg := newConfig()
+	if err = yaml.Unmarshal(buf, cfg); err != nil {
 		return nil, err
 	}
 
+	// config init used to write use_color: false to every config, which was never
+	// applied, so colors are only disabled by the flags and environment variables
+	cfg.Logs.UseColor = true
+
 	return cfg, nil
 }
 
-func Get(global bool) (*Config, error) {
+func Save(path string, cfg *Config) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	buf, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, buf, 0o644)
+}
+
+func Get(global bool, profile string) (*Config, error) {
 	cfg, err := GetStatic(global)
 	if err != nil {
-		return nil, err
+		if !errors.Is(err, ErrNotFound) || !hasEnv() {
+			return nil, err
+		}
+
+		cfg = newConfig()
+	}
+
+	if profile != "" {
+		if err = cfg.ApplyEnv(); err != nil {
+			return nil, err
+		}
+
+		if err = cfg.UseProfile(profile); err != nil {
+			return nil, err
+		}
+	} else {
+		if err = cfg.UseProfile(os.Getenv("SOAR_PROFILE")); err != nil {
+			return nil, err
+		}
+
+		if err = cfg.ApplyEnv(); err != nil {
+			return nil, err
+		}
 	}
 
 	validate := validator.New()
@@ -188,7 +300,7 @@ func Create(path string, force bool) (string, error) {
 		}
 		defer file.Close()
 
-		buf, _ := yaml.Marshal(Config{})
+		buf, _ := yaml.Marshal(newConfig())
 		file.Write(buf)
 
 		return nil
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pteropackages/soar/logger"
+)
+
+func TestLoadLogDefaults(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want LogConfig
+	}{
+		{
+			name: "missing",
+			file: "http:\n  parse_body: true\n",
+			want: LogConfig{UseColor: true},
+		},
+		{
+			name: "legacy",
+			file: "logs:\n  use_color: false\n  use_debug: false\n  ignore_warnings: false\n",
+			want: LogConfig{UseColor: true},
+		},
+		{
+			name: "set",
+			file: "logs:\n  use_color: false\n  use_debug: true\n  ignore_warnings: true\n",
+			want: LogConfig{UseColor: true, UseDebug: true, IgnoreWarnings: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".soar.yml")
+			if err := os.WriteFile(path, []byte(tt.file), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := Load(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Logs != tt.want {
+				t.Errorf("Logs = %+v, want %+v", cfg.Logs, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadLogColorEnv(t *testing.T) {
+	t.Setenv("SOAR_LOGS_USE_COLOR", "false")
+
+	path := filepath.Join(t.TempDir(), ".soar.yml")
+	if err := os.WriteFile(path, []byte("logs:\n  use_color: true\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = cfg.ApplyEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Logs.UseColor {
+		t.Error("Logs.UseColor = true, want false from the environment")
+	}
+}
+
+func TestApplyLogEnv(t *testing.T) {
+	t.Setenv("SOAR_LOGS_USE_DEBUG", "true")
+	t.Setenv("SOAR_LOGS_IGNORE_WARNINGS", "true")
+
+	cfg := newConfig()
+	if err := cfg.ApplyEnv(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := LogConfig{UseColor: true, UseDebug: true, IgnoreWarnings: true}
+	if cfg.Logs != want {
+		t.Errorf("Logs = %+v, want %+v", cfg.Logs, want)
+	}
+}
+
+func TestLogConfigApply(t *testing.T) {
+	tests := []struct {
+		name string
+		logs LogConfig
+		flag bool
+		want logger.Logger
+	}{
+		{
+			name: "defaults",
+			logs: LogConfig{UseColor: true},
+			want: logger.Logger{UseColor: true},
+		},
+		{
+			name: "config",
+			logs: LogConfig{UseColor: false, UseDebug: true, IgnoreWarnings: true},
+			want: logger.Logger{UseColor: false, UseDebug: true, IgnoreWarnings: true},
+		},
+		{
+			name: "flags",
+			logs: LogConfig{UseColor: true},
+			flag: true,
+			want: logger.Logger{UseColor: false, UseDebug: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := logger.New()
+			if tt.flag {
+				log.UseColor = false
+				log.UseDebug = true
+			}
+
+			tt.logs.Apply(log)
+			got := logger.Logger{UseColor: log.UseColor, UseDebug: log.UseDebug, IgnoreWarnings: log.IgnoreWarnings}
+			if got != tt.want {
+				t.Errorf("Apply() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const envPrefix = "SOAR_"
+
+func hasEnv() bool {
+	for _, env := range os.Environ() {
+		if strings.HasPrefix(env, envPrefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *Config) ApplyEnv() error {
+	if v, ok := os.LookupEnv("SOAR_APP_URL"); ok {
+		c.Application.URL = v
+	}
+	if v, ok := os.LookupEnv("SOAR_APP_KEY"); ok {
+		c.Application.Key = v
+	}
+	if v, ok := os.LookupEnv("SOAR_CLIENT_URL"); ok {
+		c.Client.URL = v
+	}
+	if v, ok := os.LookupEnv("SOAR_CLIENT_KEY"); ok {
+		c.Client.Key = v
+	}
+
+	if err := applyEnvFields(envPrefix+"HTTP_", reflect.ValueOf(&c.Http).Elem()); err != nil {
+		return err
+	}
+
+	return applyEnvFields(envPrefix+"LOGS_", reflect.ValueOf(&c.Logs).Elem())
+}
+
+func applyEnvFields(prefix string, v reflect.Value) error {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		key := prefix + strings.ToUpper(tag)
+		value, ok := os.LookupEnv(key)
+		if !ok {
+			continue
+		}
+
+		field := v.Field(i)
+		switch field.Kind() {
+		case reflect.Bool:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid boolean value for %s: '%s'", key, value)
+			}
+			field.SetBool(b)
+
+		case reflect.Int:
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("invalid integer value for %s: '%s'", key, value)
+			}
+			field.SetInt(int64(n))
+
+		case reflect.String:
+			field.SetString(value)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,249 @@
+package fleet
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Entry is a single resource in a manifest, keyed by the API field names.
+type Entry = map[string]interface{}
+
+// Manifest describes the desired state of a panel. Sections that are omitted are
+// left untouched when the manifest is applied.
+type Manifest struct {
+	Locations []Entry `yaml:"locations"`
+	Nodes     []Entry `yaml:"nodes"`
+	Users     []Entry `yaml:"users"`
+	Servers   []Entry `yaml:"servers"`
+}
+
+func Load(path string) (*Manifest, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(buf))
+	dec.KnownFields(true)
+
+	var m Manifest
+	if err = dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %v", err)
+	}
+
+	return &m, nil
+}
+
+func key(e Entry, field, kind string) (string, error) {
+	value, ok := e[field].(string)
+	if !ok || value == "" {
+		return "", fmt.Errorf("%s is missing the '%s' field", kind, field)
+	}
+
+	return value, nil
+}
+
+func toMap(v interface{}) map[string]interface{} {
+	buf, _ := json.Marshal(v)
+
+	var m map[string]interface{}
+	json.Unmarshal(buf, &m)
+
+	return m
+}
+
+func fromMap(m map[string]interface{}, out interface{}) error {
+	buf, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(buf, out)
+}
+
+// merge returns a copy of base with the fields of entry applied over it. Nested
+// objects are merged rather than replaced.
+func merge(base, entry map[string]interface{}) map[string]interface{} {
+	out := toMap(base)
+	mergeInto(out, toMap(entry))
+
+	return out
+}
+
+func mergeInto(dst, src map[string]interface{}) {
+	for k, v := range src {
+		if sm, ok := v.(map[string]interface{}); ok {
+			if dm, ok := dst[k].(map[string]interface{}); ok {
+				mergeInto(dm, sm)
+				continue
+			}
+		}
+
+		dst[k] = v
+	}
+}
+
+// check returns an error for fields in the entry that don't exist in the
+// template. Fields listed in free can contain any keys.
+func check(template, entry map[string]interface{}, name string, free ...string) error {
+	for k, v := range entry {
+		t, ok := template[k]
+		if !ok {
+			return fmt.Errorf("unknown field '%s' for %s", k, name)
+		}
+
+		if contains(free, k) {
+			continue
+		}
+
+		sm, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		tm, ok := t.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("field '%s' for %s is not an object", k, name)
+		}
+
+		if err := check(tm, sm, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func without(e Entry, fields ...string) Entry {
+	out := make(Entry, len(e))
+	for k, v := range e {
+		if !contains(fields, k) {
+			out[k] = v
+		}
+	}
+
+	return out
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func diff(prefix string, old, new map[string]interface{}) []Field {
+	keys := map[string]struct{}{}
+	for k := range old {
+		keys[k] = struct{}{}
+	}
+	for k := range new {
+		keys[k] = struct{}{}
+	}
+
+	names := make([]string, 0, len(keys))
+	for k := range keys {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var fields []Field
+	for _, k := range names {
+		o, n := old[k], new[k]
+		om, ook := o.(map[string]interface{})
+		nm, nok := n.(map[string]interface{})
+
+		if ook && nok {
+			fields = append(fields, diff(prefix+k+".", om, nm)...)
+		} else if !reflect.DeepEqual(o, n) {
+			fields = append(fields, Field{Name: prefix + k, Old: o, New: n})
+		}
+	}
+
+	return fields
+}
+
+// envStrings converts environment variable values to strings, which is how the
+// panel stores them, so that values written as YAML numbers or booleans compare
+// equal to the live values.
+func envStrings(env map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(env))
+	for k, v := range env {
+		switch v := v.(type) {
+		case nil, string:
+			out[k] = v
+		case float64:
+			out[k] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			out[k] = fmt.Sprint(v)
+		}
+	}
+
+	return out
+}
+
+func expandPorts(value interface{}) ([]int, error) {
+	var items []interface{}
+	switch v := value.(type) {
+	case []interface{}:
+		items = v
+	default:
+		items = []interface{}{v}
+	}
+
+	var ports []int
+	for _, item := range items {
+		spec := strings.TrimSpace(fmt.Sprint(item))
+		parts := strings.SplitN(spec, "-", 2)
+
+		start, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid port '%s'", spec)
+		}
+
+		end := start
+		if len(parts) == 2 {
+			if end, err = strconv.Atoi(parts[1]); err != nil || end < start {
+				return nil, fmt.Errorf("invalid port range '%s'", spec)
+			}
+		}
+
+		for port := start; port <= end; port++ {
+			ports = append(ports, port)
+		}
+	}
+
+	return ports, nil
+}
+
+func compactPorts(ports []int) string {
+	sort.Ints(ports)
+
+	var parts []string
+	for i := 0; i < len(ports); {
+		j := i
+		for j+1 < len(ports) && ports[j+1] == ports[j]+1 {
+			j++
+		}
+
+		if i == j {
+			parts = append(parts, strconv.Itoa(ports[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", ports[i], ports[j]))
+		}
+		i = j + 1
+	}
+
+	return strings.Join(parts, ",")
+}
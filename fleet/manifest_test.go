@@ -0,0 +1,162 @@
+package fleet
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		old  map[string]interface{}
+		new  map[string]interface{}
+		want []Field
+	}{
+		{
+			name: "equal",
+			old:  map[string]interface{}{"name": "a", "memory": 1024.0},
+			new:  map[string]interface{}{"name": "a", "memory": 1024.0},
+			want: nil,
+		},
+		{
+			name: "changed and sorted",
+			old:  map[string]interface{}{"name": "a", "memory": 1024.0},
+			new:  map[string]interface{}{"name": "b", "memory": 2048.0},
+			want: []Field{{"memory", 1024.0, 2048.0}, {"name", "a", "b"}},
+		},
+		{
+			name: "nested",
+			old:  map[string]interface{}{"limits": map[string]interface{}{"cpu": 100.0, "disk": 10.0}},
+			new:  map[string]interface{}{"limits": map[string]interface{}{"cpu": 200.0, "disk": 10.0}},
+			want: []Field{{"limits.cpu", 100.0, 200.0}},
+		},
+		{
+			name: "added and removed",
+			old:  map[string]interface{}{"a": "x"},
+			new:  map[string]interface{}{"b": "y"},
+			want: []Field{{"a", "x", nil}, {"b", nil, "y"}},
+		},
+		{
+			name: "create",
+			old:  nil,
+			new:  map[string]interface{}{"short": "us"},
+			want: []Field{{"short", nil, "us"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diff("", tt.old, tt.new); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name  string
+		base  map[string]interface{}
+		entry map[string]interface{}
+		want  map[string]interface{}
+	}{
+		{
+			name:  "override",
+			base:  map[string]interface{}{"name": "a", "memory": 1024},
+			entry: map[string]interface{}{"memory": 2048},
+			want:  map[string]interface{}{"name": "a", "memory": 2048.0},
+		},
+		{
+			name:  "nested objects are merged",
+			base:  map[string]interface{}{"limits": map[string]interface{}{"cpu": 100, "disk": 10}},
+			entry: map[string]interface{}{"limits": map[string]interface{}{"cpu": 200}},
+			want:  map[string]interface{}{"limits": map[string]interface{}{"cpu": 200.0, "disk": 10.0}},
+		},
+		{
+			name:  "object replaces scalar",
+			base:  map[string]interface{}{"allocation": 1},
+			entry: map[string]interface{}{"allocation": map[string]interface{}{"default": 2}},
+			want:  map[string]interface{}{"allocation": map[string]interface{}{"default": 2.0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := toMap(tt.base)
+			if got := merge(tt.base, tt.entry); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("merge() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(toMap(tt.base), base) {
+				t.Errorf("merge() modified the base map")
+			}
+		})
+	}
+}
+
+func TestExpandPorts(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    []int
+		wantErr bool
+	}{
+		{"single int", 25565, []int{25565}, false},
+		{"single string", "25565", []int{25565}, false},
+		{"range", "25565-25567", []int{25565, 25566, 25567}, false},
+		{"list", []interface{}{"25565-25566", 8080}, []int{25565, 25566, 8080}, false},
+		{"invalid port", "abc", nil, true},
+		{"reversed range", "25567-25565", nil, true},
+		{"invalid range end", "25565-x", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandPorts(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expandPorts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expandPorts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompactPorts(t *testing.T) {
+	tests := []struct {
+		ports []int
+		want  string
+	}{
+		{nil, ""},
+		{[]int{25565}, "25565"},
+		{[]int{25567, 25565, 25566}, "25565-25567"},
+		{[]int{25565, 25566, 25570, 8080}, "8080,25565-25566,25570"},
+	}
+
+	for _, tt := range tests {
+		if got := compactPorts(tt.ports); got != tt.want {
+			t.Errorf("compactPorts(%v) = %q, want %q", tt.ports, got, tt.want)
+		}
+	}
+}
+
+func TestEnvStrings(t *testing.T) {
+	env := map[string]interface{}{
+		"MAX_PLAYERS": 20,
+		"MEMORY":      1048576.0,
+		"EULA":        true,
+		"NAME":        "survival",
+		"EMPTY":       nil,
+	}
+	want := map[string]interface{}{
+		"MAX_PLAYERS": "20",
+		"MEMORY":      "1048576",
+		"EULA":        "true",
+		"NAME":        "survival",
+		"EMPTY":       nil,
+	}
+
+	if got := envStrings(env); !reflect.DeepEqual(got, want) {
+		t.Errorf("envStrings() = %v, want %v", got, want)
+	}
+}
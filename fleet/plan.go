@@ -0,0 +1,237 @@
+package fleet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pteropackages/soar/ptero"
+)
+
+// Pending is shown in place of an ID that will only be known once the resource it
+// refers to has been created.
+const Pending = "(known after apply)"
+
+type Field struct {
+	Name string
+	Old  interface{}
+	New  interface{}
+}
+
+type Change struct {
+	Action string
+	Kind   string
+	Name   string
+	Fields []Field
+	state  *state
+	run    func(ctx context.Context) error
+}
+
+func (c *Change) Apply(ctx context.Context) error {
+	c.state.applying = true
+	return c.run(ctx)
+}
+
+type Plan struct {
+	Changes  []*Change
+	Warnings []string
+	state    *state
+	deletes  []*Change
+}
+
+func (p *Plan) add(c *Change) {
+	c.state = p.state
+	if c.Action == "delete" {
+		p.deletes = append(p.deletes, c)
+	} else {
+		p.Changes = append(p.Changes, c)
+	}
+}
+
+func (p *Plan) Count(action string) int {
+	n := 0
+	for _, c := range p.Changes {
+		if c.Action == action {
+			n++
+		}
+	}
+
+	return n
+}
+
+func (p *Plan) Format() string {
+	var b strings.Builder
+	symbols := map[string]string{"create": "+", "update": "~", "delete": "-"}
+
+	for _, c := range p.Changes {
+		fmt.Fprintf(&b, "  %s %s %s\n", symbols[c.Action], c.Kind, c.Name)
+
+		for _, f := range c.Fields {
+			if c.Action == "create" {
+				fmt.Fprintf(&b, "      %s: %s\n", f.Name, formatValue(f.New))
+			} else {
+				fmt.Fprintf(&b, "      %s: %s => %s\n", f.Name, formatValue(f.Old), formatValue(f.New))
+			}
+		}
+	}
+
+	if len(p.Changes) == 0 {
+		b.WriteString("No changes. The panel matches the manifest.")
+	} else {
+		fmt.Fprintf(&b, "\nPlan: %d to create, %d to update, %d to delete.",
+			p.Count("create"), p.Count("update"), p.Count("delete"))
+	}
+
+	return b.String()
+}
+
+func formatValue(v interface{}) string {
+	if v == Pending {
+		return Pending
+	}
+
+	buf, _ := json.Marshal(v)
+	return string(buf)
+}
+
+type state struct {
+	app         *ptero.Application
+	locations   map[string]*ptero.Location
+	nodes       map[string]*ptero.Node
+	allocations map[int][]*ptero.Allocation
+	users       map[string]*ptero.User
+	servers     []*ptero.Server
+	planned     map[string]bool
+	applying    bool
+}
+
+func load(ctx context.Context, app *ptero.Application) (*state, error) {
+	s := &state{
+		app:         app,
+		locations:   map[string]*ptero.Location{},
+		nodes:       map[string]*ptero.Node{},
+		allocations: map[int][]*ptero.Allocation{},
+		users:       map[string]*ptero.User{},
+		planned:     map[string]bool{},
+	}
+	all := &ptero.ListOptions{All: true}
+
+	locations, _, err := app.ListLocations(ctx, all)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range locations {
+		s.locations[l.Short] = l
+	}
+
+	nodes, _, err := app.ListNodes(ctx, all)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range nodes {
+		s.nodes[n.Name] = n
+	}
+
+	users, _, err := app.ListUsers(ctx, all)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		s.users[u.Username] = u
+	}
+
+	if s.servers, _, err = app.ListServers(ctx, all); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *state) nodeAllocations(ctx context.Context, node int) ([]*ptero.Allocation, error) {
+	if allocations, ok := s.allocations[node]; ok {
+		return allocations, nil
+	}
+
+	allocations, _, err := s.app.ListNodeAllocations(ctx, node, &ptero.ListOptions{All: true})
+	if err != nil {
+		return nil, err
+	}
+	s.allocations[node] = allocations
+
+	return allocations, nil
+}
+
+// ref resolves the ID of a resource by its manifest name, returning Pending if
+// the resource is created earlier in the plan and the plan is not being applied.
+func (s *state) ref(kind, name string) (interface{}, error) {
+	switch kind {
+	case "location":
+		if l, ok := s.locations[name]; ok {
+			return l.ID, nil
+		}
+	case "node":
+		if n, ok := s.nodes[name]; ok {
+			return n.ID, nil
+		}
+	case "user":
+		if u, ok := s.users[name]; ok {
+			return u.ID, nil
+		}
+	}
+
+	if s.planned[kind+":"+name] && !s.applying {
+		return Pending, nil
+	}
+
+	return nil, fmt.Errorf("unknown %s '%s'", kind, name)
+}
+
+func (s *state) allocationRef(ctx context.Context, node, address string) (interface{}, error) {
+	if n, ok := s.nodes[node]; ok {
+		allocations, err := s.nodeAllocations(ctx, n.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, a := range allocations {
+			if fmt.Sprintf("%s:%d", a.IP, a.Port) == address {
+				return a.ID, nil
+			}
+		}
+	}
+
+	if s.planned["allocation:"+node+"/"+address] && !s.applying {
+		return Pending, nil
+	}
+
+	return nil, fmt.Errorf("unknown allocation '%s' on node '%s'", address, node)
+}
+
+// Build compares the manifest with the live state of the panel and returns the
+// changes needed to converge them. Resources that are not in the manifest are
+// only deleted if prune is set.
+func Build(ctx context.Context, app *ptero.Application, m *Manifest, prune bool) (*Plan, error) {
+	s, err := load(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+
+	// resources are deleted in the reverse order of the steps so that servers are
+	// deleted before the users and nodes they belong to
+	var deletes []*Change
+	p := &Plan{state: s}
+	steps := []func(context.Context, *state, *Plan, *Manifest, bool) error{
+		planLocations, planNodes, planUsers, planServers,
+	}
+	for _, step := range steps {
+		p.deletes = nil
+		if err = step(ctx, s, p, m, prune); err != nil {
+			return nil, err
+		}
+		deletes = append(p.deletes, deletes...)
+	}
+	p.Changes = append(p.Changes, deletes...)
+
+	return p, nil
+}
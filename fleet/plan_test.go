@@ -0,0 +1,142 @@
+package fleet
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/pteropackages/soar/ptero"
+)
+
+func list(object string, items ...map[string]interface{}) map[string]interface{} {
+	data := make([]map[string]interface{}, len(items))
+	for i, item := range items {
+		data[i] = map[string]interface{}{"object": object, "attributes": item}
+	}
+
+	return map[string]interface{}{"object": "list", "data": data}
+}
+
+// newPanel starts a panel that responds to list requests with the resources in
+// the responses map, keyed by path.
+func newPanel(t *testing.T, responses map[string]interface{}) *ptero.Application {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res, ok := responses[r.URL.Path]
+		if !ok || r.Method != http.MethodGet {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(res)
+	}))
+	t.Cleanup(srv.Close)
+
+	return ptero.NewApplicationWithKey(srv.URL, "key")
+}
+
+var defaultResponses = map[string]interface{}{
+	"/api/application/locations": list("location", map[string]interface{}{"id": 1, "short": "us", "long": "US"}),
+	"/api/application/nodes": list("node", map[string]interface{}{
+		"id": 1, "name": "node1", "location_id": 1, "fqdn": "node1.example.com",
+	}),
+	"/api/application/users": list("user", map[string]interface{}{
+		"id": 1, "username": "alice", "email": "alice@example.com",
+	}),
+	"/api/application/servers": list("server", map[string]interface{}{
+		"id": 1, "name": "survival", "user": 1, "node": 1, "egg": 5,
+		"container": map[string]interface{}{
+			"startup_command": "java",
+			"image":           "java:17",
+			"environment":     map[string]interface{}{"MAX_PLAYERS": "20", "EULA": "true"},
+		},
+	}),
+	"/api/application/nodes/1/allocations": list("allocation"),
+}
+
+func summary(p *Plan) []string {
+	var out []string
+	for _, c := range p.Changes {
+		out = append(out, c.Action+" "+c.Kind+" "+c.Name)
+	}
+
+	return out
+}
+
+func TestBuildDeleteOrder(t *testing.T) {
+	app := newPanel(t, defaultResponses)
+	m := &Manifest{
+		Locations: []Entry{{"short": "eu", "long": "Europe"}},
+		Nodes:     []Entry{},
+		Users:     []Entry{},
+		Servers:   []Entry{},
+	}
+
+	p, err := Build(context.Background(), app, m, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// creates come first, then servers are deleted before the users and nodes
+	// they belong to, and nodes before their locations
+	want := []string{
+		"create location eu",
+		"delete server survival",
+		"delete user alice",
+		"delete node node1",
+		"delete location us",
+	}
+	if got := summary(p); !reflect.DeepEqual(got, want) {
+		t.Errorf("Build() changes = %v, want %v", got, want)
+	}
+}
+
+func TestBuildWithoutPrune(t *testing.T) {
+	app := newPanel(t, defaultResponses)
+	m := &Manifest{Locations: []Entry{}, Nodes: []Entry{}, Users: []Entry{}, Servers: []Entry{}}
+
+	p, err := Build(context.Background(), app, m, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(p.Changes) != 0 {
+		t.Errorf("Build() changes = %v, want none", summary(p))
+	}
+}
+
+func TestBuildEnvironmentConverges(t *testing.T) {
+	app := newPanel(t, defaultResponses)
+	m := &Manifest{
+		Servers: []Entry{{
+			"name": "survival",
+			"environment": map[string]interface{}{
+				"MAX_PLAYERS": 20,
+				"EULA":        true,
+			},
+		}},
+	}
+
+	p, err := Build(context.Background(), app, m, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(p.Changes) != 0 {
+		t.Errorf("Build() changes = %v, want none", summary(p))
+	}
+
+	m.Servers[0]["environment"] = map[string]interface{}{"MAX_PLAYERS": 30}
+	if p, err = Build(context.Background(), app, m, false); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Field{{Name: "environment.MAX_PLAYERS", Old: "20", New: "30"}}
+	if len(p.Changes) != 1 || !reflect.DeepEqual(p.Changes[0].Fields, want) {
+		t.Errorf("Build() changes = %v, want one update with %v", summary(p), want)
+	}
+}
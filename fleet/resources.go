@@ -0,0 +1,697 @@
+package fleet
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pteropackages/soar/ptero"
+)
+
+func planLocations(ctx context.Context, s *state, p *Plan, m *Manifest, prune bool) error {
+	seen := map[string]bool{}
+	template := toMap(ptero.CreateLocationDescriptor{})
+
+	for _, e := range m.Locations {
+		short, err := key(e, "short", "location")
+		if err != nil {
+			return err
+		}
+		seen[short] = true
+
+		if err = check(template, e, "location '"+short+"'"); err != nil {
+			return err
+		}
+
+		live, ok := s.locations[short]
+		if !ok {
+			s.planned["location:"+short] = true
+			desired := merge(template, e)
+
+			p.add(&Change{
+				Action: "create",
+				Kind:   "location",
+				Name:   short,
+				Fields: diff("", nil, toMap(e)),
+				run: func(ctx context.Context) error {
+					var fields ptero.CreateLocationDescriptor
+					if err := fromMap(desired, &fields); err != nil {
+						return err
+					}
+
+					location, err := s.app.CreateLocation(ctx, fields)
+					if err != nil {
+						return err
+					}
+					s.locations[short] = location
+
+					return nil
+				},
+			})
+			continue
+		}
+
+		current := toMap(ptero.CreateLocationDescriptor{Short: live.Short, Long: live.Long})
+		desired := merge(current, e)
+		if fields := diff("", current, desired); len(fields) != 0 {
+			p.add(&Change{
+				Action: "update",
+				Kind:   "location",
+				Name:   short,
+				Fields: fields,
+				run: func(ctx context.Context) error {
+					var fields ptero.CreateLocationDescriptor
+					if err := fromMap(desired, &fields); err != nil {
+						return err
+					}
+
+					_, err := s.app.UpdateLocation(ctx, live.ID, fields)
+					return err
+				},
+			})
+		}
+	}
+
+	if !prune || m.Locations == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(s.locations))
+	for short := range s.locations {
+		if !seen[short] {
+			names = append(names, short)
+		}
+	}
+	sort.Strings(names)
+
+	for _, short := range names {
+		id := s.locations[short].ID
+		p.add(&Change{
+			Action: "delete",
+			Kind:   "location",
+			Name:   short,
+			run: func(ctx context.Context) error {
+				return s.app.DeleteLocation(ctx, id)
+			},
+		})
+	}
+
+	return nil
+}
+
+func planNodes(ctx context.Context, s *state, p *Plan, m *Manifest, prune bool) error {
+	seen := map[string]bool{}
+	template := toMap(ptero.NewNodeDescriptor())
+
+	for _, e := range m.Nodes {
+		name, err := key(e, "name", "node")
+		if err != nil {
+			return err
+		}
+		seen[name] = true
+
+		label := "node '" + name + "'"
+		location, _ := e["location"].(string)
+		entry := without(e, "location", "allocations")
+		if err = check(without(template, "location_id"), entry, label); err != nil {
+			return err
+		}
+
+		resolve := func() (map[string]interface{}, error) {
+			out := toMap(entry)
+			if location != "" {
+				id, err := s.ref("location", location)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %v", label, err)
+				}
+				out["location_id"] = id
+			}
+
+			return out, nil
+		}
+
+		desired, err := resolve()
+		if err != nil {
+			return err
+		}
+
+		live, ok := s.nodes[name]
+		if !ok {
+			if location == "" {
+				return fmt.Errorf("%s is missing the 'location' field", label)
+			}
+			s.planned["node:"+name] = true
+
+			p.add(&Change{
+				Action: "create",
+				Kind:   "node",
+				Name:   name,
+				Fields: diff("", nil, toMap(without(e, "allocations"))),
+				run: func(ctx context.Context) error {
+					desired, err := resolve()
+					if err != nil {
+						return err
+					}
+
+					var fields ptero.NodeDescriptor
+					if err = fromMap(merge(template, desired), &fields); err != nil {
+						return err
+					}
+
+					node, err := s.app.CreateNode(ctx, fields)
+					if err != nil {
+						return err
+					}
+					s.nodes[name] = node
+
+					return nil
+				},
+			})
+		} else {
+			current := toMap(live.Descriptor())
+			if fields := diff("", current, merge(current, desired)); len(fields) != 0 {
+				p.add(&Change{
+					Action: "update",
+					Kind:   "node",
+					Name:   name,
+					Fields: fields,
+					run: func(ctx context.Context) error {
+						desired, err := resolve()
+						if err != nil {
+							return err
+						}
+
+						var fields ptero.NodeDescriptor
+						if err = fromMap(merge(current, desired), &fields); err != nil {
+							return err
+						}
+
+						_, err = s.app.UpdateNode(ctx, live.ID, fields)
+						return err
+					},
+				})
+			}
+		}
+
+		if allocations, ok := e["allocations"]; ok {
+			if err = planAllocations(ctx, s, p, name, allocations, prune); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !prune || m.Nodes == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(s.nodes))
+	for name := range s.nodes {
+		if !seen[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		id := s.nodes[name].ID
+		p.add(&Change{
+			Action: "delete",
+			Kind:   "node",
+			Name:   name,
+			run: func(ctx context.Context) error {
+				return s.app.DeleteNode(ctx, id)
+			},
+		})
+	}
+
+	return nil
+}
+
+func planAllocations(ctx context.Context, s *state, p *Plan, node string, value interface{}, prune bool) error {
+	label := "allocations for node '" + node + "'"
+	items, ok := value.([]interface{})
+	if !ok {
+		return fmt.Errorf("%s must be a list", label)
+	}
+
+	type group struct {
+		ip    string
+		alias string
+		ports []int
+	}
+
+	var groups []*group
+	desired := map[string]bool{}
+
+	for _, item := range items {
+		a, ok := item.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s must be objects with the ip and ports fields", label)
+		}
+
+		if err := check(Entry{"ip": "", "alias": "", "ports": nil}, a, label); err != nil {
+			return err
+		}
+
+		ip, err := key(a, "ip", "an allocation on node '"+node+"'")
+		if err != nil {
+			return err
+		}
+
+		ports, err := expandPorts(a["ports"])
+		if err != nil {
+			return fmt.Errorf("%s: %v", label, err)
+		}
+
+		alias, _ := a["alias"].(string)
+		groups = append(groups, &group{ip: ip, alias: alias, ports: ports})
+		for _, port := range ports {
+			desired[fmt.Sprintf("%s:%d", ip, port)] = true
+		}
+	}
+
+	existing := map[string]*ptero.Allocation{}
+	if n, ok := s.nodes[node]; ok {
+		allocations, err := s.nodeAllocations(ctx, n.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, a := range allocations {
+			existing[fmt.Sprintf("%s:%d", a.IP, a.Port)] = a
+		}
+	}
+
+	for _, g := range groups {
+		var missing []int
+		for _, port := range g.ports {
+			address := fmt.Sprintf("%s:%d", g.ip, port)
+			if _, ok := existing[address]; !ok {
+				missing = append(missing, port)
+				s.planned["allocation:"+node+"/"+address] = true
+			}
+		}
+
+		if len(missing) == 0 {
+			continue
+		}
+
+		fields := ptero.CreateAllocationsDescriptor{IP: g.ip, Alias: g.alias, Ports: strings.Split(compactPorts(missing), ",")}
+		p.add(&Change{
+			Action: "create",
+			Kind:   "allocation",
+			Name:   node + " " + g.ip + ":" + compactPorts(missing),
+			run: func(ctx context.Context) error {
+				n, ok := s.nodes[node]
+				if !ok {
+					return fmt.Errorf("node '%s' does not exist", node)
+				}
+
+				if err := s.app.CreateNodeAllocations(ctx, n.ID, fields); err != nil {
+					return err
+				}
+				delete(s.allocations, n.ID)
+
+				return nil
+			},
+		})
+	}
+
+	if !prune {
+		return nil
+	}
+
+	addresses := make([]string, 0, len(existing))
+	for address := range existing {
+		if !desired[address] {
+			addresses = append(addresses, address)
+		}
+	}
+	sort.Strings(addresses)
+
+	for _, address := range addresses {
+		a := existing[address]
+		if a.Assigned {
+			p.Warnings = append(p.Warnings, fmt.Sprintf("allocation %s on node '%s' is assigned to a server and won't be deleted", address, node))
+			continue
+		}
+
+		id := s.nodes[node].ID
+		p.add(&Change{
+			Action: "delete",
+			Kind:   "allocation",
+			Name:   node + " " + address,
+			run: func(ctx context.Context) error {
+				return s.app.DeleteNodeAllocation(ctx, id, a.ID)
+			},
+		})
+	}
+
+	return nil
+}
+
+func planUsers(ctx context.Context, s *state, p *Plan, m *Manifest, prune bool) error {
+	seen := map[string]bool{}
+	template := merge(toMap(ptero.UpdateUserDescriptor{}), Entry{"password": "", "language": ""})
+
+	for _, e := range m.Users {
+		username, err := key(e, "username", "user")
+		if err != nil {
+			return err
+		}
+		seen[username] = true
+
+		if err = check(template, e, "user '"+username+"'"); err != nil {
+			return err
+		}
+
+		live, ok := s.users[username]
+		if !ok {
+			s.planned["user:"+username] = true
+			desired := merge(toMap(ptero.CreateUserDescriptor{}), e)
+
+			p.add(&Change{
+				Action: "create",
+				Kind:   "user",
+				Name:   username,
+				Fields: diff("", nil, toMap(without(e, "password"))),
+				run: func(ctx context.Context) error {
+					var fields ptero.CreateUserDescriptor
+					if err := fromMap(desired, &fields); err != nil {
+						return err
+					}
+
+					user, err := s.app.CreateUser(ctx, fields)
+					if err != nil {
+						return err
+					}
+					s.users[username] = user
+
+					return nil
+				},
+			})
+			continue
+		}
+
+		// passwords can't be compared, so they are only used to create users
+		current := toMap(live.UpdateDescriptor())
+		desired := merge(current, without(e, "password"))
+		if fields := diff("", current, desired); len(fields) != 0 {
+			p.add(&Change{
+				Action: "update",
+				Kind:   "user",
+				Name:   username,
+				Fields: fields,
+				run: func(ctx context.Context) error {
+					var fields ptero.UpdateUserDescriptor
+					if err := fromMap(desired, &fields); err != nil {
+						return err
+					}
+
+					_, err := s.app.UpdateUser(ctx, live.ID, fields)
+					return err
+				},
+			})
+		}
+	}
+
+	if !prune || m.Users == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(s.users))
+	for username := range s.users {
+		if !seen[username] {
+			names = append(names, username)
+		}
+	}
+	sort.Strings(names)
+
+	for _, username := range names {
+		id := s.users[username].ID
+		p.add(&Change{
+			Action: "delete",
+			Kind:   "user",
+			Name:   username,
+			run: func(ctx context.Context) error {
+				return s.app.DeleteUser(ctx, id)
+			},
+		})
+	}
+
+	return nil
+}
+
+// serverFields maps the manifest fields of a server to the endpoint that updates
+// them. Fields with no endpoint are only used when creating the server.
+var serverFields = map[string]string{
+	"name":                "details",
+	"description":         "details",
+	"external_id":         "details",
+	"user":                "details",
+	"allocation":          "build",
+	"limits":              "build",
+	"feature_limits":      "build",
+	"startup":             "startup",
+	"environment":         "startup",
+	"egg":                 "startup",
+	"image":               "startup",
+	"nest":                "",
+	"node":                "",
+	"skip_scripts":        "",
+	"start_on_completion": "",
+}
+
+func planServers(ctx context.Context, s *state, p *Plan, m *Manifest, prune bool) error {
+	seen := map[int]bool{}
+	template := merge(toMap(ptero.CreateServerDescriptor{}), Entry{
+		"description": "", "external_id": "", "nest": 0, "node": "", "allocation": "",
+	})
+
+	for _, e := range m.Servers {
+		e := e
+		name, err := key(e, "name", "server")
+		if err != nil {
+			return err
+		}
+
+		label := "server '" + name + "'"
+		if err = check(template, e, label, "environment"); err != nil {
+			return err
+		}
+
+		externalID, _ := e["external_id"].(string)
+		var live *ptero.Server
+		for _, srv := range s.servers {
+			if (externalID != "" && srv.ExternalID == externalID) || (externalID == "" && srv.Name == name) {
+				live = srv
+				break
+			}
+		}
+
+		resolve := func(ctx context.Context) (Entry, error) {
+			out := without(e, "nest", "node", "docker_image")
+			if image, ok := e["docker_image"]; ok {
+				out["image"] = image
+			}
+			if env, ok := e["environment"].(map[string]interface{}); ok {
+				out["environment"] = envStrings(env)
+			}
+
+			if user, ok := e["user"]; ok {
+				username, _ := user.(string)
+				id, err := s.ref("user", username)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %v", label, err)
+				}
+				out["user"] = id
+			}
+
+			if allocation, ok := e["allocation"]; ok {
+				node, _ := e["node"].(string)
+				if node == "" && live != nil {
+					for n, info := range s.nodes {
+						if info.ID == live.Node {
+							node = n
+						}
+					}
+				}
+
+				address, _ := allocation.(string)
+				id, err := s.allocationRef(ctx, node, address)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %v", label, err)
+				}
+				out["allocation"] = id
+			}
+
+			return out, nil
+		}
+
+		if _, err = resolve(ctx); err != nil {
+			return err
+		}
+
+		if live == nil {
+			for _, field := range []string{"user", "egg", "nest", "node", "allocation"} {
+				if _, ok := e[field]; !ok {
+					return fmt.Errorf("%s is missing the '%s' field", label, field)
+				}
+			}
+
+			var refs struct {
+				Nest int `json:"nest"`
+			}
+			if err = fromMap(e, &refs); err != nil {
+				return fmt.Errorf("%s: invalid nest: %v", label, err)
+			}
+
+			p.add(&Change{
+				Action: "create",
+				Kind:   "server",
+				Name:   name,
+				Fields: diff("", nil, toMap(e)),
+				run: func(ctx context.Context) error {
+					desired, err := resolve(ctx)
+					if err != nil {
+						return err
+					}
+
+					if image, ok := desired["image"]; ok {
+						desired["docker_image"] = image
+						delete(desired, "image")
+					}
+					desired["allocation"] = Entry{"default": desired["allocation"]}
+
+					var fields ptero.CreateServerDescriptor
+					if err = fromMap(desired, &fields); err != nil {
+						return err
+					}
+
+					if err = s.app.ApplyEggDefaults(ctx, refs.Nest, &fields); err != nil {
+						return err
+					}
+
+					server, err := s.app.CreateServer(ctx, fields)
+					if err != nil {
+						return err
+					}
+					s.servers = append(s.servers, server)
+
+					return nil
+				},
+			})
+			continue
+		}
+
+		seen[live.ID] = true
+		current := map[string]map[string]interface{}{
+			"details": toMap(live.DetailsDescriptor()),
+			"build":   toMap(live.BuildDescriptor()),
+			"startup": toMap(live.StartupDescriptor()),
+		}
+		if env, ok := current["startup"]["environment"].(map[string]interface{}); ok {
+			current["startup"]["environment"] = envStrings(env)
+		}
+
+		split := func(ctx context.Context) (map[string]map[string]interface{}, error) {
+			desired, err := resolve(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			sections := map[string]map[string]interface{}{}
+			for section, values := range current {
+				entry := Entry{}
+				for k, v := range desired {
+					if serverFields[k] == section {
+						entry[k] = v
+					}
+				}
+				sections[section] = merge(values, entry)
+			}
+
+			return sections, nil
+		}
+
+		sections, err := split(ctx)
+		if err != nil {
+			return err
+		}
+
+		var fields []Field
+		var changed []string
+		for _, section := range []string{"details", "build", "startup"} {
+			if d := diff("", current[section], sections[section]); len(d) != 0 {
+				fields = append(fields, d...)
+				changed = append(changed, section)
+			}
+		}
+
+		if len(fields) == 0 {
+			continue
+		}
+
+		id := live.ID
+		p.add(&Change{
+			Action: "update",
+			Kind:   "server",
+			Name:   name,
+			Fields: fields,
+			run: func(ctx context.Context) error {
+				sections, err := split(ctx)
+				if err != nil {
+					return err
+				}
+
+				for _, section := range changed {
+					switch section {
+					case "details":
+						var fields ptero.UpdateServerDetailsDescriptor
+						if err = fromMap(sections[section], &fields); err == nil {
+							_, err = s.app.UpdateServerDetails(ctx, id, fields)
+						}
+					case "build":
+						var fields ptero.UpdateServerBuildDescriptor
+						if err = fromMap(sections[section], &fields); err == nil {
+							_, err = s.app.UpdateServerBuild(ctx, id, fields)
+						}
+					case "startup":
+						var fields ptero.UpdateServerStartupDescriptor
+						if err = fromMap(sections[section], &fields); err == nil {
+							_, err = s.app.UpdateServerStartup(ctx, id, fields)
+						}
+					}
+					if err != nil {
+						return err
+					}
+				}
+
+				return nil
+			},
+		})
+	}
+
+	if !prune || m.Servers == nil {
+		return nil
+	}
+
+	for _, srv := range s.servers {
+		if seen[srv.ID] {
+			continue
+		}
+
+		id := srv.ID
+		p.add(&Change{
+			Action: "delete",
+			Kind:   "server",
+			Name:   srv.Name,
+			run: func(ctx context.Context) error {
+				return s.app.DeleteServer(ctx, id, false)
+			},
+		})
+	}
+
+	return nil
+}
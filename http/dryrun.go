@@ -0,0 +1,115 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrDryRun is returned instead of sending a request that would change the panel
+// when dry run mode is enabled.
+var ErrDryRun = errors.New("dry run enabled, the request was not sent")
+
+var dryRunMu sync.Mutex
+
+var redactedHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+	"X-Csrf-Token":  true,
+}
+
+func isMutating(method string) bool {
+	return method != http.MethodGet && method != http.MethodHead && method != http.MethodOptions
+}
+
+// dryRun prints the request that would have been sent. Delete and reinstall
+// requests also fetch and print the resource they target.
+func (c *Client) dryRun(req *http.Request) error {
+	var b strings.Builder
+	b.WriteString("dry run: " + req.Method + " " + req.URL.String() + "\n")
+
+	var keys []string
+	for k := range req.Header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := strings.Join(req.Header[k], ", ")
+		if redactedHeaders[k] {
+			v = "[redacted]"
+			if strings.HasPrefix(req.Header.Get(k), "Bearer ") {
+				v = "Bearer [redacted]"
+			}
+		}
+		b.WriteString(k + ": " + v + "\n")
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return err
+		}
+
+		buf, err := io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+
+		if len(buf) != 0 {
+			var out bytes.Buffer
+			if json.Indent(&out, buf, "", "  ") == nil {
+				buf = out.Bytes()
+			}
+			b.WriteString("\n" + string(buf) + "\n")
+		}
+	} else if req.Body != nil && req.Body != http.NoBody {
+		b.WriteString("\n(" + strings.Split(req.Header.Get("Content-Type"), ";")[0] + " body not shown)\n")
+	}
+
+	if target := dryRunTarget(req); target != "" && strings.HasPrefix(target, c.auth.URL) {
+		b.WriteString("\ntarget: GET " + target + "\n")
+
+		buf, err := c.Do(c.Request("GET", strings.TrimPrefix(target, c.auth.URL), nil).WithContext(req.Context()))
+		if err != nil {
+			b.WriteString("failed to fetch the target: " + err.Error() + "\n")
+		} else {
+			var out bytes.Buffer
+			if json.Indent(&out, buf, "", "  ") == nil {
+				buf = out.Bytes()
+			}
+			b.WriteString(string(buf) + "\n")
+		}
+	}
+
+	dryRunMu.Lock()
+	c.log.Line("%s", b.String())
+	dryRunMu.Unlock()
+
+	return ErrDryRun
+}
+
+// dryRunTarget returns the url of the resource that a delete or reinstall request
+// acts on, or an empty string for other requests.
+func dryRunTarget(req *http.Request) string {
+	u := *req.URL
+	u.RawQuery = ""
+
+	switch {
+	case req.Method == http.MethodDelete:
+		u.Path = strings.TrimSuffix(u.Path, "/force")
+	case strings.HasSuffix(u.Path, "/settings/reinstall"):
+		u.Path = strings.TrimSuffix(u.Path, "/settings/reinstall")
+	case strings.HasSuffix(u.Path, "/reinstall"):
+		u.Path = strings.TrimSuffix(u.Path, "/reinstall")
+	default:
+		return ""
+	}
+
+	return u.String()
+}
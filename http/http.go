@@ -6,28 +6,39 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pteropackages/soar/config"
 	"github.com/pteropackages/soar/logger"
-	"github.com/spf13/pflag"
 )
 
 type Client struct {
-	http   *http.Client
-	config *config.Config
-	auth   *config.Auth
-	log    *logger.Logger
+	http    *http.Client
+	config  *config.Config
+	auth    *config.Auth
+	log     *logger.Logger
+	mu      sync.Mutex
+	limiter *bucket
 }
 
 func New(cfg *config.Config, auth *config.Auth, log *logger.Logger) *Client {
-	return &Client{
+	cfg.Logs.Apply(log)
+
+	c := &Client{
 		http:   &http.Client{},
 		config: cfg,
 		auth:   auth,
 		log:    log,
 	}
+
+	if cfg.Http.RateLimit > 0 {
+		c.limiter = newBucket(cfg.Http.RateLimit)
+	}
+
+	return c
 }
 
 func Request(method, url string, body *bytes.Buffer) *http.Request {
@@ -43,6 +54,17 @@ func Request(method, url string, body *bytes.Buffer) *http.Request {
 	return req
 }
 
+// StreamRequest creates a request for an external url with a body that is read as
+// it is sent, such as a multipart upload.
+func StreamRequest(method, url string, body io.Reader) *http.Request {
+	req, _ := http.NewRequest(method, url, body)
+
+	req.Header.Set("User-Agent", "Soar Http Client")
+	req.Header.Set("Accept", "application/json")
+
+	return req
+}
+
 func (c *Client) Request(method, path string, body *bytes.Buffer) *http.Request {
 	if body == nil {
 		body = &bytes.Buffer{}
@@ -57,14 +79,18 @@ func (c *Client) Request(method, path string, body *bytes.Buffer) *http.Request
 	return req
 }
 
-type errorInfo struct {
+func (c *Client) URL() string {
+	return c.auth.URL
+}
+
+type ErrorInfo struct {
 	Code   string                 `json:"code"`
 	Status string                 `json:"status"`
 	Detail string                 `json:"detail"`
 	Meta   map[string]interface{} `json:"meta,omitempty"`
 }
 
-func (e *errorInfo) String() string {
+func (e *ErrorInfo) String() string {
 	detail := e.Detail
 	if detail == "" {
 		detail = "<no details>"
@@ -73,46 +99,96 @@ func (e *errorInfo) String() string {
 	return fmt.Sprintf("%s (%s): %s", e.Code, e.Status, detail)
 }
 
-func (c *Client) ExecuteWithFlags(req *http.Request, flags *pflag.FlagSet) ([]byte, error) {
-	query := req.URL.Query()
+type APIError struct {
+	Status  int
+	Body    []byte
+	Errors  []*ErrorInfo
+	Message string
+	panel   bool
+}
 
-	if val, _ := flags.GetInt("page"); val > 0 {
-		query.Add("page", fmt.Sprint(val))
+func (e *APIError) Error() string {
+	switch {
+	case len(e.Errors) == 1:
+		return e.Errors[0].String()
+	case len(e.Errors) > 1:
+		return fmt.Sprintf("%s (and %d more)", e.Errors[0].String(), len(e.Errors)-1)
+	case e.Message != "":
+		return e.Message
+	default:
+		return fmt.Sprintf("unknown api error: %d %s", e.Status, http.StatusText(e.Status))
 	}
+}
 
-	if val, _ := flags.GetInt("per-page"); val > 0 {
-		if val > 100 {
-			val = 100
-		}
-		query.Add("per_page", fmt.Sprint(val))
+func (e *APIError) IsNotFound() bool {
+	return e.Status == http.StatusNotFound
+}
+
+type Pagination struct {
+	Total       int             `json:"total"`
+	Count       int             `json:"count"`
+	PerPage     int             `json:"per_page"`
+	CurrentPage int             `json:"current_page"`
+	TotalPages  int             `json:"total_pages"`
+	Links       json.RawMessage `json:"links,omitempty"`
+}
+
+func (p *Pagination) next() string {
+	var links struct {
+		Next string `json:"next"`
+	}
+	if err := json.Unmarshal(p.Links, &links); err != nil || links.Next == "" {
+		return fmt.Sprint(p.CurrentPage + 1)
+	}
+
+	u, err := url.Parse(links.Next)
+	if err != nil || u.Query().Get("page") == "" {
+		return fmt.Sprint(p.CurrentPage + 1)
 	}
 
-	req.URL.RawQuery = query.Encode()
-	return c.Execute(req)
+	return u.Query().Get("page")
 }
 
-func (c *Client) Execute(req *http.Request) ([]byte, error) {
-	req.URL.Query()
+type Meta struct {
+	Pagination *Pagination `json:"pagination,omitempty"`
+}
+
+func (c *Client) Do(req *http.Request) ([]byte, error) {
+	res, err := c.Stream(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNoContent {
+		return nil, nil
+	}
+
+	return io.ReadAll(res.Body)
+}
+
+// Stream sends the request and returns the response without reading the body,
+// which must be closed by the caller. Error responses are returned as an APIError.
+func (c *Client) Stream(req *http.Request) (*http.Response, error) {
+	if err := checkFormat(c.config); err != nil {
+		return nil, err
+	}
+
+	if c.config.Http.DryRun && isMutating(req.Method) {
+		return nil, c.dryRun(req)
+	}
+
 	c.log.Ignore().Info("request %s %s", req.Method, req.URL.Path)
 	c.log.Debug("%s %s", req.Method, req.URL.String())
 	c.log.Debug("Content-Type: %s", req.Header.Get("Content-Type"))
 	c.log.Debug("Content-Length: %s", req.Header.Get("Content-Length"))
 	c.log.Debug("Accept: %s", req.Header.Get("Accept"))
 
-	start := time.Now()
-
-	res, err := http.DefaultClient.Do(req)
+	res, err := c.send(req)
 	if err != nil {
 		return nil, err
 	}
 
-	taken := time.Since(start).Microseconds() / 1000
-	c.log.Debug("response %d (%vms)", res.StatusCode, taken)
-	c.log.Ignore().Info("response %d (%dms)", res.StatusCode, taken)
-	for k, v := range res.Header {
-		c.log.Debug("%s: %v", k, strings.Join(v, ","))
-	}
-
 	switch res.StatusCode {
 	case http.StatusOK:
 		fallthrough
@@ -121,15 +197,17 @@ func (c *Client) Execute(req *http.Request) ([]byte, error) {
 		fallthrough
 
 	case http.StatusAccepted:
-		defer res.Body.Close()
-		buf, err := io.ReadAll(res.Body)
-		return buf, err
+		fallthrough
 
 	case http.StatusNoContent:
-		return nil, nil
+		fallthrough
+
+	case http.StatusPartialContent:
+		return res, nil
 
 	default:
 		defer res.Body.Close()
+
 		buf, err := io.ReadAll(res.Body)
 		if err != nil {
 			return nil, fmt.Errorf("unknown api error: %s", res.Status)
@@ -138,45 +216,140 @@ func (c *Client) Execute(req *http.Request) ([]byte, error) {
 		c.log.Debug("host: %s", res.Request.Host)
 		c.log.Debug(string(buf))
 
-		if c.config.Http.ParseErrors {
-			if strings.Contains(c.auth.URL, res.Request.Host) {
-				var data struct {
-					Errors []*errorInfo `json:"errors"`
-				}
-				if err = json.Unmarshal(buf, &data); err != nil {
-					return nil, err
-				}
-
-				c.log.Error("received %d error(s):", len(data.Errors))
-				for _, e := range data.Errors {
-					c.log.Error(e.String())
-				}
-			} else {
-				var data struct {
-					Error string `json:"error"`
-				}
-				if err = json.Unmarshal(buf, &data); err != nil {
-					return nil, err
-				}
-
-				c.log.Error("received an error:").Error(data.Error)
+		e := &APIError{
+			Status: res.StatusCode,
+			Body:   buf,
+			panel:  strings.Contains(c.auth.URL, res.Request.Host),
+		}
+
+		if e.panel {
+			var data struct {
+				Errors []*ErrorInfo `json:"errors"`
+			}
+			if err = json.Unmarshal(buf, &data); err == nil {
+				e.Errors = data.Errors
 			}
 		} else {
-			if c.config.Http.ParseIndent {
-				var raw interface{}
-				if err = json.Unmarshal(buf, &raw); err != nil {
-					return nil, err
-				}
-
-				buf, err = json.MarshalIndent(raw, "", "  ")
-				if err != nil {
-					return nil, err
-				}
+			var data struct {
+				Error string `json:"error"`
+			}
+			if err = json.Unmarshal(buf, &data); err == nil {
+				e.Message = data.Error
 			}
+		}
+
+		return nil, e
+	}
+}
+
+func (c *Client) DoAll(req *http.Request) ([]byte, error) {
+	var object string
+	var data []json.RawMessage
+
+	for {
+		res, err := c.Do(req)
+		if err != nil || res == nil {
+			return nil, err
+		}
 
-			c.log.LineB(buf)
+		var model struct {
+			O string            `json:"object"`
+			D []json.RawMessage `json:"data"`
+			M struct {
+				P *Pagination `json:"pagination"`
+			} `json:"meta"`
 		}
+		if err = json.Unmarshal(res, &model); err != nil {
+			return nil, err
+		}
+
+		if model.M.P == nil {
+			if object == "" {
+				return res, nil
+			}
+			break
+		}
+
+		object = model.O
+		data = append(data, model.D...)
+		c.log.Debug("fetched page %d of %d", model.M.P.CurrentPage, model.M.P.TotalPages)
+
+		if model.M.P.CurrentPage >= model.M.P.TotalPages || len(model.D) == 0 {
+			break
+		}
+
+		query := req.URL.Query()
+		query.Set("page", model.M.P.next())
+		req = req.Clone(req.Context())
+		req.URL.RawQuery = query.Encode()
+	}
+
+	if data == nil {
+		data = []json.RawMessage{}
+	}
+
+	return json.Marshal(map[string]interface{}{"object": object, "data": data})
+}
 
+func (c *Client) Execute(req *http.Request) ([]byte, error) {
+	buf, err := c.Do(req)
+	if _, ok := err.(*APIError); ok {
+		HandleError(err, c.config, c.log)
 		return nil, nil
 	}
+
+	return buf, err
+}
+
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	retries := c.config.Http.MaxRetries
+	if retries <= 0 {
+		retries = defaultMaxRetries
+	}
+
+	for attempt := 0; ; attempt++ {
+		c.throttle()
+		start := time.Now()
+
+		res, err := c.http.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		taken := time.Since(start).Microseconds() / 1000
+		c.log.Debug("response %d (%vms)", res.StatusCode, taken)
+		c.log.Ignore().Info("response %d (%dms)", res.StatusCode, taken)
+		for k, v := range res.Header {
+			c.log.Debug("%s: %v", k, strings.Join(v, ","))
+		}
+
+		c.updateLimiter(res.Header)
+
+		if res.StatusCode != http.StatusTooManyRequests || !c.config.Http.RetryRateLimit {
+			return res, nil
+		}
+
+		if attempt >= retries {
+			c.log.Warn("ratelimited after %d retries, giving up", retries)
+			return res, nil
+		}
+
+		if req.Body != nil && req.GetBody == nil {
+			return res, nil
+		}
+
+		wait := retryAfter(res.Header, attempt)
+		res.Body.Close()
+
+		c.log.Ignore().Info("ratelimited, retrying in %s (attempt %d of %d)", wait, attempt+1, retries)
+		time.Sleep(wait)
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
 }
@@ -0,0 +1,131 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pteropackages/soar/config"
+)
+
+func TestDoAll(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+
+		page := r.URL.Query().Get("page")
+		if page == "" {
+			page = "1"
+		}
+
+		var data []int
+		switch page {
+		case "1":
+			data = []int{1, 2}
+		case "2":
+			data = []int{3, 4}
+		case "3":
+			data = []int{5}
+		}
+
+		next := ""
+		if page != "3" {
+			next = fmt.Sprintf("http://%s%s?page=%c&per_page=2", r.Host, r.URL.Path, page[0]+1)
+		}
+
+		items := make([]map[string]interface{}, len(data))
+		for i, id := range data {
+			items[i] = map[string]interface{}{"object": "user", "attributes": map[string]int{"id": id}}
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"object": "list",
+			"data":   items,
+			"meta": map[string]interface{}{
+				"pagination": map[string]interface{}{
+					"total":        5,
+					"count":        len(data),
+					"per_page":     2,
+					"current_page": int(page[0] - '0'),
+					"total_pages":  3,
+					"links":        map[string]string{"next": next},
+				},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	c := newTestClient(&config.Config{}, srv.URL)
+	buf, err := c.DoAll(c.Request("GET", "/api/application/users?per_page=2", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var model struct {
+		O string `json:"object"`
+		D []struct {
+			A struct {
+				ID int `json:"id"`
+			} `json:"attributes"`
+		} `json:"data"`
+		M interface{} `json:"meta"`
+	}
+	if err = json.Unmarshal(buf, &model); err != nil {
+		t.Fatal(err)
+	}
+
+	if model.O != "list" {
+		t.Errorf("object = %s, want list", model.O)
+	}
+	if model.M != nil {
+		t.Errorf("meta = %v, want none", model.M)
+	}
+
+	var ids []int
+	for _, d := range model.D {
+		ids = append(ids, d.A.ID)
+	}
+	if fmt.Sprint(ids) != "[1 2 3 4 5]" {
+		t.Errorf("ids = %v, want [1 2 3 4 5]", ids)
+	}
+
+	want := []string{"per_page=2", "page=2&per_page=2", "page=3&per_page=2"}
+	if fmt.Sprint(queries) != fmt.Sprint(want) {
+		t.Errorf("queries = %v, want %v", queries, want)
+	}
+}
+
+func TestDoAllWithoutPagination(t *testing.T) {
+	body := `{"object":"server","attributes":{"id":1}}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(&config.Config{}, srv.URL)
+	buf, err := c.DoAll(c.Request("GET", "/api/application/servers/1", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != body {
+		t.Errorf("DoAll() = %s, want the response unchanged", buf)
+	}
+}
+
+func TestDoAllEmpty(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"object":"list","data":[],"meta":{"pagination":{"total":0,"count":0,"per_page":50,"current_page":1,"total_pages":1}}}`))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(&config.Config{}, srv.URL)
+	buf, err := c.DoAll(c.Request("GET", "/api/application/servers", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != `{"data":[],"object":"list"}` {
+		t.Errorf("DoAll() = %s, want an empty list", buf)
+	}
+}
@@ -0,0 +1,540 @@
+package http
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/pteropackages/soar/config"
+	"gopkg.in/yaml.v3"
+)
+
+var formats = []string{"json", "yaml", "table", "csv", "wide", "go-template"}
+
+type column struct {
+	header string
+	fields []string
+	format func(interface{}) string
+	wide   bool
+}
+
+func col(header string, fields ...string) column {
+	return column{header: header, fields: fields}
+}
+
+func (c column) bytes() column {
+	c.format = formatBytes
+	return c
+}
+
+func (c column) extra() column {
+	c.wide = true
+	return c
+}
+
+var columns = map[string][]column{
+	"activity_log": {
+		col("EVENT", "event"),
+		col("IP", "ip"),
+		col("TIMESTAMP", "timestamp"),
+		col("ID", "id").extra(),
+		col("API", "is_api").extra(),
+		col("DESCRIPTION", "description").extra(),
+	},
+	"allocation": {
+		col("ID", "id"),
+		col("IP", "ip"),
+		col("PORT", "port"),
+		col("ALIAS", "alias"),
+		col("ASSIGNED", "assigned"),
+		col("NOTES", "notes").extra(),
+	},
+	"api_key": {
+		col("IDENTIFIER", "identifier"),
+		col("DESCRIPTION", "description"),
+		col("LAST USED", "last_used_at"),
+		col("ALLOWED IPS", "allowed_ips").extra(),
+		col("CREATED", "created_at").extra(),
+		col("TOKEN", "token").extra(),
+	},
+	"backup": {
+		col("UUID", "uuid"),
+		col("NAME", "name"),
+		col("SIZE", "bytes").bytes(),
+		col("SUCCESSFUL", "is_successful"),
+		col("LOCKED", "is_locked"),
+		col("CREATED", "created_at"),
+		col("COMPLETED", "completed_at").extra(),
+		col("CHECKSUM", "checksum").extra(),
+		col("IGNORED", "ignored_files").extra(),
+	},
+	"egg": {
+		col("ID", "id"),
+		col("NAME", "name"),
+		col("NEST", "nest"),
+		col("DOCKER IMAGE", "docker_image"),
+		col("UUID", "uuid").extra(),
+		col("AUTHOR", "author").extra(),
+	},
+	"egg_variable": {
+		col("NAME", "name"),
+		col("ENV", "env_variable"),
+		col("VALUE", "server_value"),
+		col("EDITABLE", "is_editable"),
+		col("DEFAULT", "default_value").extra(),
+		col("RULES", "rules").extra(),
+	},
+	"file_object": {
+		col("NAME", "name"),
+		col("SIZE", "size").bytes(),
+		col("MODE", "mode"),
+		col("MODIFIED", "modified_at"),
+		col("MIMETYPE", "mimetype").extra(),
+		col("SYMLINK", "is_symlink").extra(),
+		col("CREATED", "created_at").extra(),
+	},
+	"location": {
+		col("ID", "id"),
+		col("SHORT", "short"),
+		col("LONG", "long"),
+		col("CREATED", "created_at").extra(),
+		col("UPDATED", "updated_at").extra(),
+	},
+	"nest": {
+		col("ID", "id"),
+		col("NAME", "name"),
+		col("AUTHOR", "author"),
+		col("UUID", "uuid").extra(),
+		col("DESCRIPTION", "description").extra(),
+	},
+	"node": {
+		col("ID", "id"),
+		col("NAME", "name"),
+		col("FQDN", "fqdn"),
+		col("LOCATION", "location_id"),
+		col("MAINTENANCE", "maintenance_mode"),
+		col("MEMORY", "memory").extra(),
+		col("DISK", "disk").extra(),
+		col("PUBLIC", "public").extra(),
+		col("UUID", "uuid").extra(),
+	},
+	"schedule_task": {
+		col("ID", "id"),
+		col("SEQUENCE", "sequence_id"),
+		col("ACTION", "action"),
+		col("PAYLOAD", "payload"),
+		col("OFFSET", "time_offset"),
+		col("CONTINUE ON FAILURE", "continue_on_failure").extra(),
+		col("QUEUED", "is_queued").extra(),
+	},
+	"server": {
+		col("ID", "id", "identifier"),
+		col("NAME", "name"),
+		col("NODE", "node"),
+		col("STATUS", "status"),
+		col("UUID", "uuid").extra(),
+		col("MEMORY", "limits.memory").extra(),
+		col("DISK", "limits.disk").extra(),
+		col("CPU", "limits.cpu").extra(),
+		col("SUSPENDED", "suspended", "is_suspended").extra(),
+	},
+	"server_allocation": {
+		col("ID", "id"),
+		col("IP", "ip"),
+		col("PORT", "port"),
+		col("PRIMARY", "is_default"),
+		col("NOTES", "notes"),
+		col("ALIAS", "ip_alias").extra(),
+	},
+	"server_database": {
+		col("ID", "id"),
+		col("NAME", "name"),
+		col("USERNAME", "username"),
+		col("HOST", "host.address"),
+		col("PORT", "host.port"),
+		col("PASSWORD", "password").extra(),
+		col("REMOTE", "connections_from").extra(),
+		col("MAX CONNECTIONS", "max_connections").extra(),
+	},
+	"server_schedule": {
+		col("ID", "id"),
+		col("NAME", "name"),
+		{header: "CRON", fields: []string{"cron"}, format: formatCron},
+		col("ACTIVE", "is_active"),
+		col("NEXT RUN", "next_run_at"),
+		col("ONLINE ONLY", "only_when_online").extra(),
+		col("LAST RUN", "last_run_at").extra(),
+		col("PROCESSING", "is_processing").extra(),
+	},
+	"server_stats": {
+		col("ID", "identifier"),
+		col("NAME", "name"),
+		col("STATE", "current_state"),
+		col("CPU", "resources.cpu_absolute"),
+		col("MEMORY", "resources.memory_bytes").bytes(),
+		col("DISK", "resources.disk_bytes").bytes(),
+		col("NET RX", "resources.network_rx_bytes").bytes(),
+		col("NET TX", "resources.network_tx_bytes").bytes(),
+		col("UPTIME", "resources.uptime").extra(),
+	},
+	"server_subuser": {
+		col("UUID", "uuid"),
+		col("USERNAME", "username"),
+		col("EMAIL", "email"),
+		col("2FA", "2fa_enabled"),
+		col("PERMISSIONS", "permissions").extra(),
+		col("CREATED", "created_at").extra(),
+	},
+	"ssh_key": {
+		col("NAME", "name"),
+		col("FINGERPRINT", "fingerprint"),
+		col("CREATED", "created_at"),
+		col("PUBLIC KEY", "public_key").extra(),
+	},
+	"stats": {
+		col("STATE", "current_state"),
+		col("MEMORY", "resources.memory_bytes").bytes(),
+		col("CPU", "resources.cpu_absolute"),
+		col("DISK", "resources.disk_bytes").bytes(),
+		col("NET RX", "resources.network_rx_bytes").bytes().extra(),
+		col("NET TX", "resources.network_tx_bytes").bytes().extra(),
+		col("UPTIME", "resources.uptime").extra(),
+	},
+	"user": {
+		col("ID", "id"),
+		col("USERNAME", "username"),
+		col("EMAIL", "email"),
+		col("ADMIN", "root_admin", "admin"),
+		col("UUID", "uuid").extra(),
+		col("2FA", "2fa").extra(),
+		col("CREATED", "created_at").extra(),
+	},
+}
+
+func parseFormat(cfg *config.Config) (string, string) {
+	parts := strings.SplitN(cfg.Http.Output, "=", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+
+	return parts[0], parts[1]
+}
+
+// checkFormat returns an error if the output format is unknown or its template
+// is invalid, so that commands fail before sending any requests.
+func checkFormat(cfg *config.Config) error {
+	format, arg := parseFormat(cfg)
+
+	switch format {
+	case "", "json", "yaml", "table", "wide", "csv":
+		return nil
+
+	case "go-template":
+		if arg == "" {
+			return fmt.Errorf("a template must be specified (e.g. go-template='{{.name}}')")
+		}
+		if _, err := template.New("output").Parse(arg); err != nil {
+			return fmt.Errorf("failed to parse template: %v", err)
+		}
+
+		return nil
+
+	default:
+		return fmt.Errorf("unknown output format '%s' (must be one of: %s)", format, strings.Join(formats, ", "))
+	}
+}
+
+func isTabular(cfg *config.Config) bool {
+	switch format, _ := parseFormat(cfg); format {
+	case "table", "wide", "csv":
+		return true
+	default:
+		return false
+	}
+}
+
+func formatData(v interface{}, cfg *config.Config) ([]byte, error) {
+	if err := checkFormat(cfg); err != nil {
+		return nil, err
+	}
+
+	format, arg := parseFormat(cfg)
+
+	switch format {
+	case "yaml":
+		data, err := toGeneric(v)
+		if err != nil {
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err = enc.Encode(integers(data)); err != nil {
+			return nil, err
+		}
+
+		return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+
+	case "go-template":
+		tmpl, err := template.New("output").Parse(arg)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := toGeneric(v)
+		if err != nil {
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+		if err = tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("failed to execute template: %v", err)
+		}
+
+		return buf.Bytes(), nil
+
+	case "table", "wide", "csv":
+		data, err := toGeneric(v)
+		if err != nil {
+			return nil, err
+		}
+
+		return formatRows("", []interface{}{data}, cfg)
+
+	default:
+		if cfg.Http.ParseIndent {
+			return json.MarshalIndent(v, "", "  ")
+		}
+
+		return json.Marshal(v)
+	}
+}
+
+func formatRows(object string, rows []interface{}, cfg *config.Config) ([]byte, error) {
+	format, _ := parseFormat(cfg)
+	cols := columnsFor(object, rows, format != "table")
+
+	var buf bytes.Buffer
+
+	if format == "csv" {
+		w := csv.NewWriter(&buf)
+		header := make([]string, 0, len(cols))
+		for _, c := range cols {
+			header = append(header, strings.ToLower(strings.ReplaceAll(c.header, " ", "_")))
+		}
+		w.Write(header)
+
+		for _, row := range rows {
+			record := make([]string, 0, len(cols))
+			for _, c := range cols {
+				value := lookup(row, c.fields)
+				if c.format != nil {
+					record = append(record, c.format(value))
+				} else {
+					record = append(record, formatValue(value))
+				}
+			}
+			w.Write(record)
+		}
+
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return nil, err
+		}
+	} else {
+		w := tabwriter.NewWriter(&buf, 0, 0, 3, ' ', 0)
+		header := make([]string, 0, len(cols))
+		for _, c := range cols {
+			header = append(header, c.header)
+		}
+		fmt.Fprintln(w, strings.Join(header, "\t"))
+
+		for _, row := range rows {
+			cells := make([]string, 0, len(cols))
+			for _, c := range cols {
+				value := lookup(row, c.fields)
+				if c.format != nil {
+					cells = append(cells, c.format(value))
+				} else {
+					cells = append(cells, formatValue(value))
+				}
+			}
+			fmt.Fprintln(w, strings.Join(cells, "\t"))
+		}
+
+		if err := w.Flush(); err != nil {
+			return nil, err
+		}
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func columnsFor(object string, rows []interface{}, wide bool) []column {
+	if defined, ok := columns[object]; ok {
+		cols := make([]column, 0, len(defined))
+		for _, c := range defined {
+			if !c.wide || wide {
+				cols = append(cols, c)
+			}
+		}
+
+		return cols
+	}
+
+	keys := map[string]struct{}{}
+	for _, row := range rows {
+		m, ok := row.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for k, v := range m {
+			switch v.(type) {
+			case map[string]interface{}, []interface{}:
+			default:
+				keys[k] = struct{}{}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(keys))
+	for k := range keys {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	cols := make([]column, 0, len(names))
+	for _, k := range names {
+		cols = append(cols, col(strings.ToUpper(strings.ReplaceAll(k, "_", " ")), k))
+	}
+
+	return cols
+}
+
+func toGeneric(v interface{}) (interface{}, error) {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var data interface{}
+	if err = json.Unmarshal(buf, &data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func integers(v interface{}) interface{} {
+	switch value := v.(type) {
+	case float64:
+		if value == math.Trunc(value) && math.Abs(value) < 1e15 {
+			return int64(value)
+		}
+	case map[string]interface{}:
+		for k, item := range value {
+			value[k] = integers(item)
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = integers(item)
+		}
+	}
+
+	return v
+}
+
+func lookup(row interface{}, fields []string) interface{} {
+	for _, field := range fields {
+		value, ok := row, true
+
+		for _, key := range strings.Split(field, ".") {
+			m, isMap := value.(map[string]interface{})
+			if !isMap {
+				ok = false
+				break
+			}
+
+			if value, ok = m[key]; !ok {
+				break
+			}
+		}
+
+		if ok {
+			return value
+		}
+	}
+
+	return nil
+}
+
+func formatValue(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	case []interface{}:
+		parts := make([]string, 0, len(value))
+		for _, item := range value {
+			parts = append(parts, formatValue(item))
+		}
+
+		return strings.Join(parts, ",")
+	default:
+		buf, _ := json.Marshal(value)
+		return string(buf)
+	}
+}
+
+func formatCron(v interface{}) string {
+	cron, ok := v.(map[string]interface{})
+	if !ok {
+		return formatValue(v)
+	}
+
+	fields := []string{"minute", "hour", "day_of_month", "month", "day_of_week"}
+	parts := make([]string, 0, len(fields))
+	for _, f := range fields {
+		parts = append(parts, formatValue(cron[f]))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func formatBytes(v interface{}) string {
+	size, ok := v.(float64)
+	if !ok {
+		return formatValue(v)
+	}
+
+	return FormatBytes(size)
+}
+
+func FormatBytes(size float64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	i := 0
+	for size >= 1024 && i < len(units)-1 {
+		size /= 1024
+		i++
+	}
+
+	if i == 0 {
+		return fmt.Sprintf("%.0f %s", size, units[i])
+	}
+
+	return fmt.Sprintf("%.1f %s", size, units[i])
+}
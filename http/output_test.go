@@ -0,0 +1,95 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pteropackages/soar/config"
+	"github.com/pteropackages/soar/logger"
+)
+
+func TestCheckFormat(t *testing.T) {
+	tests := []struct {
+		output string
+		err    bool
+	}{
+		{output: "", err: false},
+		{output: "json", err: false},
+		{output: "yaml", err: false},
+		{output: "wide", err: false},
+		{output: "csv", err: false},
+		{output: "go-template={{.name}}", err: false},
+		{output: "go-template", err: true},
+		{output: "go-template={{.name", err: true},
+		{output: "bogus", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			cfg := &config.Config{Http: config.HttpConfig{Output: tt.output}}
+			if err := checkFormat(cfg); (err != nil) != tt.err {
+				t.Errorf("checkFormat() error = %v, want error %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestStreamChecksFormat(t *testing.T) {
+	sent := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent++
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{Http: config.HttpConfig{Output: "bogus"}}
+	c := New(cfg, &config.Auth{URL: srv.URL, Key: "key"}, logger.New())
+
+	if _, err := c.Do(c.Request("POST", "/api/application/servers", nil)); err == nil {
+		t.Error("Do() error = nil, want unknown output format")
+	}
+	if sent != 0 {
+		t.Errorf("sent %d request(s), want 0", sent)
+	}
+}
+
+func TestFormatRows(t *testing.T) {
+	rows := []interface{}{
+		map[string]interface{}{
+			"id":   1.0,
+			"name": "restart",
+			"cron": map[string]interface{}{
+				"minute": "0", "hour": "4", "day_of_month": "*", "month": "*", "day_of_week": "*",
+			},
+			"is_active":   true,
+			"next_run_at": nil,
+		},
+	}
+
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{
+			output: "table",
+			want:   "ID   NAME      CRON        ACTIVE   NEXT RUN\n1    restart   0 4 * * *   true     ",
+		},
+		{
+			output: "csv",
+			want:   "id,name,cron,active,next_run,online_only,last_run,processing\n1,restart,0 4 * * *,true,,,,",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			cfg := &config.Config{Http: config.HttpConfig{Output: tt.output}}
+			got, err := formatRows("server_schedule", rows, cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("formatRows() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package http
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const defaultMaxRetries = 3
+
+type bucket struct {
+	mu       sync.Mutex
+	capacity float64
+	tokens   float64
+	rate     float64
+	last     time.Time
+}
+
+func newBucket(perMinute int) *bucket {
+	return &bucket{
+		capacity: float64(perMinute),
+		tokens:   float64(perMinute),
+		rate:     float64(perMinute) / 60,
+		last:     time.Now(),
+	}
+}
+
+func (b *bucket) refill() {
+	now := time.Now()
+	b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+}
+
+// take reserves a token from the bucket and returns how long the caller must
+// wait before sending the request.
+func (b *bucket) take() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// sync lowers the available tokens to the remaining count reported by the
+// panel, so that requests made by other clients are also accounted for.
+func (b *bucket) sync(remaining int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	if float64(remaining) < b.tokens {
+		b.tokens = float64(remaining)
+	}
+}
+
+func (c *Client) throttle() {
+	c.mu.Lock()
+	limiter := c.limiter
+	c.mu.Unlock()
+
+	if limiter == nil {
+		return
+	}
+
+	if wait := limiter.take(); wait > 0 {
+		c.log.Ignore().Info("approaching ratelimit, waiting %s", wait.Round(time.Millisecond))
+		time.Sleep(wait)
+	}
+}
+
+func (c *Client) updateLimiter(header http.Header) {
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil || limit <= 0 {
+		return
+	}
+
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	if c.limiter == nil {
+		c.limiter = newBucket(limit)
+	}
+	limiter := c.limiter
+	c.mu.Unlock()
+
+	limiter.sync(remaining)
+}
+
+func retryAfter(header http.Header, attempt int) time.Duration {
+	if v := header.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil {
+			return time.Duration(secs) * time.Second
+		}
+
+		if date, err := http.ParseTime(v); err == nil {
+			if wait := time.Until(date); wait > 0 {
+				return wait
+			}
+		}
+	}
+
+	if v := header.Get("X-RateLimit-Reset"); v != "" {
+		if reset, err := strconv.ParseInt(v, 10, 64); err == nil {
+			if wait := time.Until(time.Unix(reset, 0)); wait > 0 {
+				return wait
+			}
+		}
+	}
+
+	return time.Duration(1<<attempt) * time.Second
+}
@@ -0,0 +1,190 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pteropackages/soar/config"
+	"github.com/pteropackages/soar/logger"
+)
+
+func newTestClient(cfg *config.Config, url string) *Client {
+	log := logger.New()
+	log.Quiet = true
+
+	return New(cfg, &config.Auth{URL: url, Key: "key"}, log)
+}
+
+func TestBucket(t *testing.T) {
+	b := newBucket(60)
+	for i := 0; i < 60; i++ {
+		if wait := b.take(); wait != 0 {
+			t.Fatalf("take() %d = %s, want 0", i, wait)
+		}
+	}
+
+	// the bucket refills one token per second
+	if wait := b.take(); wait < 900*time.Millisecond || wait > time.Second {
+		t.Errorf("take() = %s, want about 1s", wait)
+	}
+
+	b = newBucket(60)
+	b.sync(0)
+	if wait := b.take(); wait < 900*time.Millisecond || wait > time.Second {
+		t.Errorf("take() after sync = %s, want about 1s", wait)
+	}
+
+	b = newBucket(60)
+	b.sync(100)
+	if wait := b.take(); wait != 0 {
+		t.Errorf("take() after sync above capacity = %s, want 0", wait)
+	}
+}
+
+func TestUpdateLimiter(t *testing.T) {
+	c := newTestClient(&config.Config{}, "http://localhost")
+
+	c.updateLimiter(http.Header{})
+	if c.limiter != nil {
+		t.Fatal("limiter created without ratelimit headers")
+	}
+
+	c.updateLimiter(http.Header{"X-Ratelimit-Limit": {"60"}, "X-Ratelimit-Remaining": {"0"}})
+	if c.limiter == nil {
+		t.Fatal("limiter not created from ratelimit headers")
+	}
+	if wait := c.limiter.take(); wait <= 0 {
+		t.Errorf("take() = %s, want a wait with no remaining requests", wait)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  http.Header
+		attempt int
+		min     time.Duration
+		max     time.Duration
+	}{
+		{
+			name:   "seconds",
+			header: http.Header{"Retry-After": {"2"}},
+			min:    2 * time.Second,
+			max:    2 * time.Second,
+		},
+		{
+			name:   "date",
+			header: http.Header{"Retry-After": {time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat)}},
+			min:    8 * time.Second,
+			max:    10 * time.Second,
+		},
+		{
+			name:   "reset",
+			header: http.Header{"X-Ratelimit-Reset": {strconv.FormatInt(time.Now().Add(10*time.Second).Unix(), 10)}},
+			min:    8 * time.Second,
+			max:    10 * time.Second,
+		},
+		{
+			name:   "reset in the past",
+			header: http.Header{"X-Ratelimit-Reset": {strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)}},
+			min:    time.Second,
+			max:    time.Second,
+		},
+		{
+			name:    "exponential",
+			header:  http.Header{},
+			attempt: 2,
+			min:     4 * time.Second,
+			max:     4 * time.Second,
+		},
+		{
+			name:    "invalid",
+			header:  http.Header{"Retry-After": {"soon"}},
+			attempt: 1,
+			min:     2 * time.Second,
+			max:     2 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryAfter(tt.header, tt.attempt); got < tt.min || got > tt.max {
+				t.Errorf("retryAfter() = %s, want between %s and %s", got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+// newRatelimitedPanel starts a panel that responds with 429 to the first limited
+// requests and records the body of every request.
+func newRatelimitedPanel(t *testing.T, limited int) (*httptest.Server, *[]string) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(buf))
+
+		if len(bodies) <= limited {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		w.Write([]byte(`{"object":"server"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &bodies
+}
+
+func TestSendRetries(t *testing.T) {
+	srv, bodies := newRatelimitedPanel(t, 2)
+	c := newTestClient(&config.Config{Http: config.HttpConfig{RetryRateLimit: true}}, srv.URL)
+
+	buf, err := c.Do(c.Request("POST", "/api/application/servers", bytes.NewBufferString(`{"name":"survival"}`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != `{"object":"server"}` {
+		t.Errorf("Do() = %s, want the server", buf)
+	}
+
+	if len(*bodies) != 3 {
+		t.Fatalf("sent %d request(s), want 3", len(*bodies))
+	}
+	for i, body := range *bodies {
+		if body != `{"name":"survival"}` {
+			t.Errorf("request %d body = %q, want the original body", i, body)
+		}
+	}
+}
+
+func TestSendGivesUp(t *testing.T) {
+	srv, bodies := newRatelimitedPanel(t, 5)
+	c := newTestClient(&config.Config{Http: config.HttpConfig{RetryRateLimit: true, MaxRetries: 1}}, srv.URL)
+	c.log.IgnoreWarnings = true
+
+	_, err := c.Do(c.Request("GET", "/api/application/servers", nil))
+	if e, ok := err.(*APIError); !ok || e.Status != http.StatusTooManyRequests {
+		t.Errorf("Do() error = %v, want a 429 api error", err)
+	}
+	if len(*bodies) != 2 {
+		t.Errorf("sent %d request(s), want 2", len(*bodies))
+	}
+}
+
+func TestSendWithoutRetry(t *testing.T) {
+	srv, bodies := newRatelimitedPanel(t, 1)
+	c := newTestClient(&config.Config{}, srv.URL)
+
+	if _, err := c.Do(c.Request("GET", "/api/application/servers", nil)); err == nil {
+		t.Error("Do() error = nil, want a 429 api error")
+	}
+	if len(*bodies) != 1 {
+		t.Errorf("sent %d request(s), want 1", len(*bodies))
+	}
+}
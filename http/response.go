@@ -2,77 +2,126 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
+	"reflect"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/pteropackages/soar/config"
+	"github.com/pteropackages/soar/logger"
 )
 
-func HandleItemResponse(buf []byte, cfg *config.Config) ([]byte, error) {
-	var model struct {
-		O string      `json:"object"`
-		A interface{} `json:"attributes"`
+type fractalItem struct {
+	O string      `json:"object"`
+	A interface{} `json:"attributes"`
+}
+
+type fractalList struct {
+	O string        `json:"object"`
+	D []fractalItem `json:"data"`
+	M interface{}   `json:"meta,omitempty"`
+}
+
+func HandleJSON(v interface{}, cfg *config.Config) ([]byte, error) {
+	return formatData(v, cfg)
+}
+
+func HandleItem(object string, v interface{}, cfg *config.Config) ([]byte, error) {
+	if isTabular(cfg) {
+		data, err := toGeneric(v)
+		if err != nil {
+			return nil, err
+		}
+
+		return formatRows(object, []interface{}{data}, cfg)
 	}
-	if err := json.Unmarshal(buf, &model); err != nil {
-		return nil, err
+
+	if cfg.Http.ParseBody {
+		return HandleJSON(v, cfg)
 	}
 
-	var res []byte
-	var err error
+	return HandleJSON(fractalItem{O: object, A: v}, cfg)
+}
+
+func HandleList(object string, v, meta interface{}, cfg *config.Config) ([]byte, error) {
+	items := reflect.ValueOf(v)
+	if items.Kind() != reflect.Slice {
+		return nil, errors.New("list response must be a slice")
+	}
+
+	if isTabular(cfg) {
+		data, err := toGeneric(v)
+		if err != nil {
+			return nil, err
+		}
+
+		rows, _ := data.([]interface{})
+		return formatRows(object, rows, cfg)
+	}
 
 	if cfg.Http.ParseBody {
-		if cfg.Http.ParseIndent {
-			res, err = json.MarshalIndent(model.A, "", "  ")
-		} else {
-			res, err = json.Marshal(model.A)
+		if items.IsNil() {
+			return HandleJSON([]interface{}{}, cfg)
 		}
 
-		return res, err
+		return HandleJSON(v, cfg)
 	}
 
-	if cfg.Http.ParseIndent {
-		res, err = json.MarshalIndent(model, "", "  ")
-	} else {
-		res, err = json.Marshal(model)
+	model := fractalList{O: "list", D: make([]fractalItem, 0, items.Len())}
+	for i := 0; i < items.Len(); i++ {
+		model.D = append(model.D, fractalItem{O: object, A: items.Index(i).Interface()})
 	}
 
-	return res, err
+	if m := reflect.ValueOf(meta); meta != nil && !(m.Kind() == reflect.Ptr && m.IsNil()) {
+		model.M = meta
+	}
+
+	return HandleJSON(model, cfg)
 }
 
-func HandleDataResponse(buf []byte, cfg *config.Config) ([]byte, error) {
-	var model struct {
-		O string `json:"object"`
-		D []struct {
-			O string      `json:"object"`
-			A interface{} `json:"attributes"`
-		} `json:"data"`
-		M map[string]interface{} `json:"meta,omitempty"`
+func HandleError(err error, cfg *config.Config, log *logger.Logger) {
+	// the request was already printed
+	if errors.Is(err, ErrDryRun) {
+		return
 	}
-	if err := json.Unmarshal(buf, &model); err != nil {
-		return nil, err
+
+	var errs validator.ValidationErrors
+	if errors.As(err, &errs) {
+		log.Error("failed to validate fields, %d error(s):", len(errs))
+		for _, e := range errs {
+			log.Error("field %s didn't satisfy the '%s' tag", e.Field(), e.Tag())
+		}
+		return
 	}
 
-	var res []byte
-	var err error
+	var e *APIError
+	if !errors.As(err, &e) {
+		log.WithError(err)
+		return
+	}
 
-	if cfg.Http.ParseBody {
-		inner := make([]interface{}, 0, len(model.D))
-		for _, m := range model.D {
-			inner = append(inner, m.A)
-		}
+	if !cfg.Http.ParseErrors {
+		buf := e.Body
 
 		if cfg.Http.ParseIndent {
-			res, err = json.MarshalIndent(inner, "", "  ")
-		} else {
-			res, err = json.Marshal(inner)
+			var raw interface{}
+			if err = json.Unmarshal(buf, &raw); err == nil {
+				buf, _ = json.MarshalIndent(raw, "", "  ")
+			}
 		}
 
-		return res, err
+		log.LineB(buf)
+		return
 	}
 
-	if cfg.Http.ParseIndent {
-		res, err = json.MarshalIndent(model, "", "  ")
-	} else {
-		res, err = json.Marshal(model)
+	switch {
+	case len(e.Errors) != 0:
+		log.Error("received %d error(s):", len(e.Errors))
+		for _, info := range e.Errors {
+			log.Error(info.String())
+		}
+	case e.Message != "":
+		log.Error("received an error:").Error(e.Message)
+	default:
+		log.WithError(e)
 	}
-
-	return res, err
 }
@@ -15,18 +15,31 @@ const (
 	NullStringNode
 	NumberNode
 	BoolNode
+	ArrayNumberNode
 )
 
 type Definition map[string]Node
 
 func Marshal(def Definition, input map[string]string) ([]byte, error) {
+	return marshal(def, input, false)
+}
+
+func MarshalPartial(def Definition, input map[string]string) ([]byte, error) {
+	return marshal(def, input, true)
+}
+
+func marshal(def Definition, input map[string]string, partial bool) ([]byte, error) {
 	p := map[string]interface{}{}
 
 	for k, n := range def {
 		v, ok := input[k]
 		if !ok {
+			if partial {
+				continue
+			}
+
 			if n == NullStringNode {
-				p[k] = nil
+				set(p, k, nil)
 				continue
 			}
 
@@ -35,14 +48,14 @@ func Marshal(def Definition, input map[string]string) ([]byte, error) {
 
 		switch n {
 		case StringNode:
-			p[k] = v
+			set(p, k, v)
 		case ArrayStringNode:
-			p[k] = strings.Split(v, ",")
+			set(p, k, strings.Split(v, ","))
 		case NullStringNode:
 			if v == "null" {
-				p[k] = nil
+				set(p, k, nil)
 			} else {
-				p[k] = v
+				set(p, k, v)
 			}
 		case NumberNode:
 			r, err := strconv.ParseInt(v, 10, 64)
@@ -50,16 +63,44 @@ func Marshal(def Definition, input map[string]string) ([]byte, error) {
 				return nil, fmt.Errorf("invalid integer \"%s\"", v)
 			}
 
-			p[k] = r
+			set(p, k, r)
 		case BoolNode:
 			r, err := strconv.ParseBool(v)
 			if err != nil {
 				return nil, fmt.Errorf("invalid boolean: \"%s\"", v)
 			}
 
-			p[k] = r
+			set(p, k, r)
+		case ArrayNumberNode:
+			var r []int64
+			for _, part := range strings.Split(v, ",") {
+				i, err := strconv.ParseInt(part, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid integer \"%s\"", part)
+				}
+
+				r = append(r, i)
+			}
+
+			set(p, k, r)
 		}
 	}
 
 	return json.Marshal(p)
 }
+
+// set assigns the value to the key in the map, creating nested maps for
+// dotted keys (e.g. "limits.memory").
+func set(p map[string]interface{}, key string, value interface{}) {
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := p[part].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			p[part] = next
+		}
+		p = next
+	}
+
+	p[parts[len(parts)-1]] = value
+}
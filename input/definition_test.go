@@ -0,0 +1,79 @@
+package input
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		name string
+		base map[string]interface{}
+		key  string
+		want map[string]interface{}
+	}{
+		{
+			name: "top level",
+			base: map[string]interface{}{},
+			key:  "name",
+			want: map[string]interface{}{"name": "x"},
+		},
+		{
+			name: "nested",
+			base: map[string]interface{}{},
+			key:  "limits.memory",
+			want: map[string]interface{}{"limits": map[string]interface{}{"memory": "x"}},
+		},
+		{
+			name: "existing map",
+			base: map[string]interface{}{"limits": map[string]interface{}{"disk": 10}},
+			key:  "limits.memory",
+			want: map[string]interface{}{"limits": map[string]interface{}{"disk": 10, "memory": "x"}},
+		},
+		{
+			name: "replaces value",
+			base: map[string]interface{}{"limits": "none"},
+			key:  "limits.memory",
+			want: map[string]interface{}{"limits": map[string]interface{}{"memory": "x"}},
+		},
+		{
+			name: "deeply nested",
+			base: map[string]interface{}{},
+			key:  "a.b.c",
+			want: map[string]interface{}{"a": map[string]interface{}{"b": map[string]interface{}{"c": "x"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set(tt.base, tt.key, "x")
+			if !reflect.DeepEqual(tt.base, tt.want) {
+				t.Errorf("set() = %v, want %v", tt.base, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshalPartial(t *testing.T) {
+	def := Definition{
+		"name":              StringNode,
+		"limits.memory":     NumberNode,
+		"limits.disk":       NumberNode,
+		"feature_limits.db": NumberNode,
+		"oom_disabled":      BoolNode,
+	}
+
+	got, err := MarshalPartial(def, map[string]string{"limits.memory": "2048", "limits.disk": "0", "oom_disabled": "true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"limits":{"disk":0,"memory":2048},"oom_disabled":true}`
+	if string(got) != want {
+		t.Errorf("MarshalPartial() = %s, want %s", got, want)
+	}
+
+	if _, err = MarshalPartial(def, map[string]string{"limits.memory": "lots"}); err == nil {
+		t.Error("MarshalPartial() error = nil, want invalid integer")
+	}
+}
@@ -106,7 +106,7 @@ func readValue(r *strings.Reader) (string, error) {
 			return "", err
 		}
 
-		if (d && c == '"') || c == ' ' {
+		if (d && c == '"') || (!d && c == ' ') {
 			break
 		}
 
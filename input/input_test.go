@@ -0,0 +1,63 @@
+package input
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  map[string]string
+		err   bool
+	}{
+		{
+			name:  "single",
+			input: "name=survival",
+			want:  map[string]string{"name": "survival"},
+		},
+		{
+			name:  "multiple",
+			input: "name=survival  memory=1024",
+			want:  map[string]string{"name": "survival", "memory": "1024"},
+		},
+		{
+			name:  "quoted",
+			input: `name="survival server" memory=1024`,
+			want:  map[string]string{"name": "survival server", "memory": "1024"},
+		},
+		{
+			name:  "quoted last",
+			input: `memory=1024 description="events and minigames"`,
+			want:  map[string]string{"memory": "1024", "description": "events and minigames"},
+		},
+		{
+			name:  "dotted key",
+			input: "limits.memory=2048",
+			want:  map[string]string{"limits.memory": "2048"},
+		},
+		{
+			name:  "empty",
+			input: "",
+			want:  map[string]string{},
+		},
+		{
+			name:  "missing value",
+			input: "name=",
+			err:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if (err != nil) != tt.err {
+				t.Fatalf("Parse() error = %v, want error %v", err, tt.err)
+			}
+			if !tt.err && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -9,20 +9,22 @@ import (
 )
 
 type Logger struct {
-	UseColor bool
-	UseDebug bool
-	Quiet    bool
-	ignore   bool
-	writer   *os.File
+	UseColor       bool
+	UseDebug       bool
+	IgnoreWarnings bool
+	Quiet          bool
+	ignore         bool
+	writer         *os.File
 }
 
 func New() *Logger {
 	return &Logger{
-		UseColor: true,
-		UseDebug: false,
-		Quiet:    false,
-		ignore:   false,
-		writer:   os.Stdout,
+		UseColor:       true,
+		UseDebug:       false,
+		IgnoreWarnings: false,
+		Quiet:          false,
+		ignore:         false,
+		writer:         os.Stdout,
 	}
 }
 
@@ -84,7 +86,7 @@ func (l *Logger) Line(data string, args ...interface{}) *Logger {
 }
 
 func (l *Logger) LineB(data []byte) {
-	l.Line(string(data))
+	l.Line("%s", data)
 }
 
 func (l *Logger) WithCmd(cmd string) *Logger {
@@ -92,14 +94,16 @@ func (l *Logger) WithCmd(cmd string) *Logger {
 	return l
 }
 
+// Ignore returns a copy of the logger that skips the next info message in quiet
+// mode. The copy keeps concurrent requests from racing on the shared logger.
 func (l *Logger) Ignore() *Logger {
-	l.ignore = l.Quiet
-	return l
+	c := *l
+	c.ignore = l.Quiet
+	return &c
 }
 
 func (l *Logger) Info(data string, args ...interface{}) {
 	if l.ignore {
-		l.ignore = false
 		return
 	}
 
@@ -108,6 +112,10 @@ func (l *Logger) Info(data string, args ...interface{}) {
 }
 
 func (l *Logger) Warn(data string, args ...interface{}) {
+	if l.IgnoreWarnings {
+		return
+	}
+
 	l.writer.WriteString(l.color("$Ywarn$Z: "))
 	l.writer.WriteString(fmt.Sprintf(data, args...) + "\n")
 }
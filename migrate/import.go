@@ -0,0 +1,440 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pteropackages/soar/http"
+	"github.com/pteropackages/soar/ptero"
+	"gopkg.in/yaml.v3"
+)
+
+// Mapping records the IDs of resources on the source panel and the IDs of the
+// matching resources on the target panel.
+type Mapping struct {
+	Version     int         `yaml:"version"`
+	Locations   map[int]int `yaml:"locations"`
+	Nodes       map[int]int `yaml:"nodes"`
+	Allocations map[int]int `yaml:"allocations"`
+	Users       map[int]int `yaml:"users"`
+	Eggs        map[int]int `yaml:"eggs"`
+	Servers     map[int]int `yaml:"servers"`
+}
+
+// LoadMapping reads the mapping file at the path, or returns an empty mapping if
+// the file doesn't exist.
+func LoadMapping(path string) (*Mapping, error) {
+	m := &Mapping{Version: Version}
+
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+	} else if err = yaml.Unmarshal(buf, m); err != nil {
+		err = fmt.Errorf("failed to parse mapping file: %v", err)
+	} else if m.Version != Version {
+		err = fmt.Errorf("unsupported mapping file version %d (expected %d)", m.Version, Version)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ids := range []*map[int]int{&m.Locations, &m.Nodes, &m.Allocations, &m.Users, &m.Eggs, &m.Servers} {
+		if *ids == nil {
+			*ids = map[int]int{}
+		}
+	}
+
+	return m, nil
+}
+
+func (m *Mapping) Save(path string) error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(m); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// Importer recreates the resources of a snapshot on a panel. Resources that are
+// already in the mapping are skipped, and resources that exist on the panel are
+// matched instead of created, so an import can be run again after a failure.
+type Importer struct {
+	App         *ptero.Application
+	Mapping     *Mapping
+	SkipScripts bool
+	// OnChange is called after each resource is matched or created, so that the
+	// mapping can be saved as the import progresses.
+	OnChange func(action, kind, name string, from, to int) error
+}
+
+func (i *Importer) Run(ctx context.Context, s *Snapshot) error {
+	steps := []func(context.Context, *Snapshot) error{
+		i.mapEggs, i.importLocations, i.importNodes, i.importUsers, i.importServers,
+	}
+	for _, step := range steps {
+		if err := step(ctx, s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i *Importer) record(ids map[int]int, action, kind, name string, from, to int) error {
+	ids[from] = to
+	if i.OnChange == nil {
+		return nil
+	}
+
+	return i.OnChange(action, kind, name, from, to)
+}
+
+// mapEggs matches the eggs used by servers in the snapshot with eggs on the panel
+// by their nest and egg names. Eggs can't be created through the API, so an error
+// is returned if any can't be matched.
+func (i *Importer) mapEggs(ctx context.Context, s *Snapshot) error {
+	names := map[int]string{}
+	for _, n := range s.Nests {
+		for _, e := range n.Eggs {
+			names[e.ID] = n.Name + "/" + e.Name
+		}
+	}
+
+	var missing []string
+	var target map[string]int
+
+	for _, srv := range s.Servers {
+		if _, ok := i.Mapping.Eggs[srv.Egg]; ok {
+			continue
+		}
+
+		if target == nil {
+			target = map[string]int{}
+			nests, _, err := i.App.ListNests(ctx, &ptero.ListOptions{All: true})
+			if err != nil {
+				return err
+			}
+
+			for _, n := range nests {
+				eggs, _, err := i.App.ListNestEggs(ctx, n.ID, &ptero.ListOptions{All: true})
+				if err != nil {
+					return err
+				}
+
+				for _, e := range eggs {
+					target[n.Name+"/"+e.Name] = e.ID
+				}
+			}
+		}
+
+		name, ok := names[srv.Egg]
+		if !ok {
+			name = fmt.Sprintf("egg %d", srv.Egg)
+		}
+
+		id, ok := target[name]
+		if !ok {
+			missing = append(missing, fmt.Sprintf("%s (id %d)", name, srv.Egg))
+			continue
+		}
+
+		if err := i.record(i.Mapping.Eggs, "matched", "egg", name, srv.Egg, id); err != nil {
+			return err
+		}
+	}
+
+	if len(missing) != 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("eggs can't be created through the api, import them on the panel or add them to the mapping file: %s",
+			strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+func (i *Importer) importLocations(ctx context.Context, s *Snapshot) error {
+	existing, _, err := i.App.ListLocations(ctx, &ptero.ListOptions{All: true})
+	if err != nil {
+		return err
+	}
+
+	for _, l := range s.Locations {
+		if _, ok := i.Mapping.Locations[l.ID]; ok {
+			continue
+		}
+
+		action, id := "matched", 0
+		for _, e := range existing {
+			if e.Short == l.Short {
+				id = e.ID
+			}
+		}
+
+		if id == 0 {
+			location, err := i.App.CreateLocation(ctx, ptero.CreateLocationDescriptor{Short: l.Short, Long: l.Long})
+			if err != nil {
+				return err
+			}
+			action, id = "created", location.ID
+		}
+
+		if err = i.record(i.Mapping.Locations, action, "location", l.Short, l.ID, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i *Importer) importNodes(ctx context.Context, s *Snapshot) error {
+	existing, _, err := i.App.ListNodes(ctx, &ptero.ListOptions{All: true})
+	if err != nil {
+		return err
+	}
+
+	for _, n := range s.Nodes {
+		id, ok := i.Mapping.Nodes[n.ID]
+		if !ok {
+			action := "matched"
+			for _, e := range existing {
+				if e.Name == n.Name {
+					id = e.ID
+				}
+			}
+
+			if id == 0 {
+				fields := n.Descriptor()
+				if fields.LocationID, ok = i.Mapping.Locations[n.LocationID]; !ok {
+					return fmt.Errorf("node '%s' uses location %d which is not in the mapping", n.Name, n.LocationID)
+				}
+
+				node, err := i.App.CreateNode(ctx, fields)
+				if err != nil {
+					return err
+				}
+				action, id = "created", node.ID
+			}
+
+			if err = i.record(i.Mapping.Nodes, action, "node", n.Name, n.ID, id); err != nil {
+				return err
+			}
+		}
+
+		if err = i.importAllocations(ctx, n, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i *Importer) importAllocations(ctx context.Context, n *Node, node int) error {
+	type group struct {
+		ip    string
+		alias string
+	}
+
+	var pending []*ptero.Allocation
+	for _, a := range n.Allocations {
+		if _, ok := i.Mapping.Allocations[a.ID]; !ok {
+			pending = append(pending, a)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	existing, _, err := i.App.ListNodeAllocations(ctx, node, &ptero.ListOptions{All: true})
+	if err != nil {
+		return err
+	}
+
+	addresses := map[string]int{}
+	for _, a := range existing {
+		addresses[fmt.Sprintf("%s:%d", a.IP, a.Port)] = a.ID
+	}
+
+	var groups []group
+	ports := map[group][]string{}
+	for _, a := range pending {
+		if _, ok := addresses[fmt.Sprintf("%s:%d", a.IP, a.Port)]; ok {
+			continue
+		}
+
+		g := group{ip: a.IP, alias: a.Alias}
+		if _, ok := ports[g]; !ok {
+			groups = append(groups, g)
+		}
+		ports[g] = append(ports[g], fmt.Sprint(a.Port))
+	}
+
+	if len(groups) != 0 {
+		for _, g := range groups {
+			fields := ptero.CreateAllocationsDescriptor{IP: g.ip, Alias: g.alias, Ports: ports[g]}
+			if err = i.App.CreateNodeAllocations(ctx, node, fields); err != nil {
+				return err
+			}
+		}
+
+		if existing, _, err = i.App.ListNodeAllocations(ctx, node, &ptero.ListOptions{All: true}); err != nil {
+			return err
+		}
+	}
+
+	created := map[string]bool{}
+	for _, a := range existing {
+		address := fmt.Sprintf("%s:%d", a.IP, a.Port)
+		if _, ok := addresses[address]; !ok {
+			created[address] = true
+		}
+		addresses[address] = a.ID
+	}
+
+	for _, a := range pending {
+		address := fmt.Sprintf("%s:%d", a.IP, a.Port)
+		id, ok := addresses[address]
+		if !ok {
+			return fmt.Errorf("allocation %s was not created on node '%s'", address, n.Name)
+		}
+
+		action := "matched"
+		if created[address] {
+			action = "created"
+		}
+
+		if err = i.record(i.Mapping.Allocations, action, "allocation", address, a.ID, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i *Importer) importUsers(ctx context.Context, s *Snapshot) error {
+	existing, _, err := i.App.ListUsers(ctx, &ptero.ListOptions{All: true})
+	if err != nil {
+		return err
+	}
+
+	for _, u := range s.Users {
+		if _, ok := i.Mapping.Users[u.ID]; ok {
+			continue
+		}
+
+		// users are only matched by email, as the same username on two panels is
+		// often a different person
+		action, id := "matched", 0
+		for _, e := range existing {
+			if strings.EqualFold(e.Email, u.Email) {
+				id = e.ID
+				break
+			}
+		}
+
+		if id == 0 {
+			for _, e := range existing {
+				if strings.EqualFold(e.Username, u.Username) {
+					return fmt.Errorf("user '%s' (%s, id %d) has the same username as user %d (%s) on the panel; "+
+						"add the pair to the users section of the mapping file if they are the same person, "+
+						"or change one of the usernames", u.Username, u.Email, u.ID, e.ID, e.Email)
+				}
+			}
+		}
+
+		if id == 0 {
+			user, err := i.App.CreateUser(ctx, ptero.CreateUserDescriptor{
+				Username:   u.Username,
+				Email:      u.Email,
+				ExternalID: u.ExternalID,
+				FirstName:  u.FirstName,
+				LastName:   u.LastName,
+				RootAdmin:  u.RootAdmin,
+			})
+			if err != nil {
+				return err
+			}
+			action, id = "created", user.ID
+		}
+
+		if err = i.record(i.Mapping.Users, action, "user", u.Username, u.ID, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i *Importer) importServers(ctx context.Context, s *Snapshot) error {
+	for _, srv := range s.Servers {
+		if _, ok := i.Mapping.Servers[srv.ID]; ok {
+			continue
+		}
+
+		if srv.ExternalID != "" {
+			existing, err := i.App.GetServerExternal(ctx, srv.ExternalID)
+			if err == nil {
+				if err = i.record(i.Mapping.Servers, "matched", "server", srv.Name, srv.ID, existing.ID); err != nil {
+					return err
+				}
+				continue
+			}
+
+			var e *http.APIError
+			if !errors.As(err, &e) || !e.IsNotFound() {
+				return err
+			}
+		}
+
+		user, ok := i.Mapping.Users[srv.User]
+		if !ok {
+			return fmt.Errorf("server '%s' uses user %d which is not in the mapping", srv.Name, srv.User)
+		}
+
+		allocation, ok := i.Mapping.Allocations[srv.Allocation]
+		if !ok {
+			return fmt.Errorf("server '%s' uses allocation %d which is not in the mapping", srv.Name, srv.Allocation)
+		}
+
+		// the panel adds its own variables to the environment which are not egg variables
+		env := map[string]interface{}{}
+		for k, v := range srv.Container.Environment {
+			if k != "STARTUP" && !strings.HasPrefix(k, "P_SERVER_") {
+				env[k] = v
+			}
+		}
+
+		created, err := i.App.CreateServer(ctx, ptero.CreateServerDescriptor{
+			Name:          srv.Name,
+			Description:   srv.Description,
+			ExternalID:    srv.ExternalID,
+			User:          user,
+			Egg:           i.Mapping.Eggs[srv.Egg],
+			DockerImage:   srv.Container.Image,
+			Startup:       srv.Container.StartupCommand,
+			Environment:   env,
+			Limits:        srv.Limits,
+			FeatureLimits: srv.FeatureLimits,
+			Allocation:    &ptero.AllocationDescriptor{Default: allocation},
+			SkipScripts:   i.SkipScripts,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err = i.record(i.Mapping.Servers, "created", "server", srv.Name, srv.ID, created.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
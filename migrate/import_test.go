@@ -0,0 +1,389 @@
+package migrate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pteropackages/soar/ptero"
+)
+
+// panel is a target panel that lists the resources it holds and adds created
+// resources to them, recording each request that changes it.
+type panel struct {
+	t         *testing.T
+	resources map[string][]map[string]interface{}
+	objects   map[string]string
+	next      int
+	changes   []string
+	bodies    map[string][]map[string]interface{}
+}
+
+func newPanel(t *testing.T, resources map[string][]map[string]interface{}) (*panel, *ptero.Application) {
+	p := &panel{
+		t:         t,
+		resources: resources,
+		objects: map[string]string{
+			"/api/application/locations": "location",
+			"/api/application/nodes":     "node",
+			"/api/application/users":     "user",
+			"/api/application/servers":   "server",
+		},
+		next:   1000,
+		bodies: map[string][]map[string]interface{}{},
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(p.serve))
+	t.Cleanup(srv.Close)
+
+	return p, ptero.NewApplicationWithKey(srv.URL, "key")
+}
+
+func (p *panel) serve(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/api/application/servers/external/") {
+		id := strings.TrimPrefix(r.URL.Path, "/api/application/servers/external/")
+		for _, s := range p.resources["/api/application/servers"] {
+			if s["external_id"] == id {
+				json.NewEncoder(w).Encode(map[string]interface{}{"object": "server", "attributes": s})
+				return
+			}
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"errors":[{"code":"NotFoundHttpException","status":"404","detail":"not found"}]}`))
+		return
+	}
+
+	items, ok := p.resources[r.URL.Path]
+	if !ok {
+		p.t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		data := make([]map[string]interface{}, len(items))
+		for i, item := range items {
+			data[i] = map[string]interface{}{"object": p.objects[r.URL.Path], "attributes": item}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"object": "list", "data": data})
+
+	case http.MethodPost:
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		p.changes = append(p.changes, "POST "+r.URL.Path)
+		p.bodies[r.URL.Path] = append(p.bodies[r.URL.Path], body)
+
+		// allocations are created in bulk from a list of ports
+		if strings.HasSuffix(r.URL.Path, "/allocations") {
+			for _, port := range body["ports"].([]interface{}) {
+				p.next++
+				var n int
+				fmt.Sscan(port.(string), &n)
+				p.resources[r.URL.Path] = append(p.resources[r.URL.Path], map[string]interface{}{
+					"id": p.next, "ip": body["ip"], "alias": body["alias"], "port": n,
+				})
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		p.next++
+		attrs := map[string]interface{}{"id": p.next}
+		for k, v := range body {
+			attrs[k] = v
+		}
+		if a, ok := body["allocation"].(map[string]interface{}); ok {
+			attrs["allocation"] = a["default"]
+		}
+		p.resources[r.URL.Path] = append(items, attrs)
+		json.NewEncoder(w).Encode(map[string]interface{}{"object": p.objects[r.URL.Path], "attributes": attrs})
+
+	default:
+		p.t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func targetResources() map[string][]map[string]interface{} {
+	return map[string][]map[string]interface{}{
+		"/api/application/locations": {{"id": 10, "short": "us", "long": "US"}},
+		"/api/application/nodes":     {{"id": 20, "name": "node1", "location_id": 10}},
+		"/api/application/nodes/20/allocations": {
+			{"id": 200, "ip": "10.0.0.1", "port": 25565},
+		},
+		"/api/application/users": {
+			{"id": 30, "username": "alice2", "email": "ALICE@example.com"},
+		},
+		"/api/application/nests":        {{"id": 3, "name": "Minecraft"}},
+		"/api/application/nests/3/eggs": {{"id": 15, "name": "Paper", "nest": 3}},
+		"/api/application/servers":      {},
+	}
+}
+
+func sourceSnapshot() *Snapshot {
+	return &Snapshot{
+		Locations: []*ptero.Location{{ID: 1, Short: "us", Long: "US"}, {ID: 2, Short: "eu", Long: "Europe"}},
+		Nodes: []*Node{{
+			Node: &ptero.Node{ID: 1, Name: "node1", LocationID: 1},
+			Allocations: []*ptero.Allocation{
+				{ID: 100, IP: "10.0.0.1", Port: 25565},
+				{ID: 101, IP: "10.0.0.1", Port: 25566},
+				{ID: 102, IP: "10.0.0.2", Alias: "play", Port: 25565},
+				{ID: 103, IP: "10.0.0.1", Port: 25567},
+			},
+		}},
+		Users: []*ptero.User{
+			{ID: 1, Username: "alice", Email: "alice@example.com"},
+			{ID: 2, Username: "bob", Email: "bob@example.com"},
+		},
+		Nests: []*Nest{{
+			Nest: &ptero.Nest{ID: 1, Name: "Minecraft"},
+			Eggs: []*ptero.Egg{{ID: 5, Name: "Paper", Nest: 1}},
+		}},
+		Servers: []*ptero.Server{{
+			ID: 1, Name: "survival", User: 1, Allocation: 101, Egg: 5,
+			Container: ptero.Container{
+				Image:          "java:17",
+				StartupCommand: "java -jar server.jar",
+				Environment:    map[string]interface{}{"EULA": "true", "STARTUP": "java", "P_SERVER_LOCATION": "us"},
+			},
+		}},
+	}
+}
+
+func newMapping() *Mapping {
+	return &Mapping{
+		Version:     Version,
+		Locations:   map[int]int{},
+		Nodes:       map[int]int{},
+		Allocations: map[int]int{},
+		Users:       map[int]int{},
+		Eggs:        map[int]int{},
+		Servers:     map[int]int{},
+	}
+}
+
+func TestImport(t *testing.T) {
+	p, app := newPanel(t, targetResources())
+
+	var log []string
+	i := &Importer{App: app, Mapping: newMapping(), OnChange: func(action, kind, name string, from, to int) error {
+		log = append(log, fmt.Sprintf("%s %s %s", action, kind, name))
+		return nil
+	}}
+	if err := i.Run(context.Background(), sourceSnapshot()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Mapping{
+		Version:     Version,
+		Locations:   map[int]int{1: 10, 2: 1001},
+		Nodes:       map[int]int{1: 20},
+		Allocations: map[int]int{100: 200, 101: 1002, 102: 1004, 103: 1003},
+		Users:       map[int]int{1: 30, 2: 1005},
+		Eggs:        map[int]int{5: 15},
+		Servers:     map[int]int{1: 1006},
+	}
+	if !reflect.DeepEqual(i.Mapping, want) {
+		t.Errorf("mapping = %+v, want %+v", i.Mapping, want)
+	}
+
+	wantLog := []string{
+		"matched egg Minecraft/Paper",
+		"matched location us",
+		"created location eu",
+		"matched node node1",
+		"matched allocation 10.0.0.1:25565",
+		"created allocation 10.0.0.1:25566",
+		"created allocation 10.0.0.2:25565",
+		"created allocation 10.0.0.1:25567",
+		"matched user alice",
+		"created user bob",
+		"created server survival",
+	}
+	if !reflect.DeepEqual(log, wantLog) {
+		t.Errorf("changes = %q, want %q", log, wantLog)
+	}
+
+	// allocations are created once for each ip and alias
+	allocations := p.bodies["/api/application/nodes/20/allocations"]
+	if len(allocations) != 2 {
+		t.Fatalf("created allocations %d time(s), want 2", len(allocations))
+	}
+	if fmt.Sprintf("%v %v", allocations[0]["ip"], allocations[0]["ports"]) != "10.0.0.1 [25566 25567]" {
+		t.Errorf("first allocations = %v, want 10.0.0.1 with ports 25566 and 25567", allocations[0])
+	}
+	if fmt.Sprintf("%v %v %v", allocations[1]["ip"], allocations[1]["alias"], allocations[1]["ports"]) != "10.0.0.2 play [25565]" {
+		t.Errorf("second allocations = %v, want 10.0.0.2 (play) with port 25565", allocations[1])
+	}
+
+	server := p.bodies["/api/application/servers"][0]
+	if server["user"] != 30.0 || server["egg"] != 15.0 {
+		t.Errorf("server user and egg = %v and %v, want 30 and 15", server["user"], server["egg"])
+	}
+	if a, _ := server["allocation"].(map[string]interface{}); a["default"] != 1002.0 {
+		t.Errorf("server allocation = %v, want 1002", server["allocation"])
+	}
+	if env := server["environment"]; !reflect.DeepEqual(env, map[string]interface{}{"EULA": "true"}) {
+		t.Errorf("server environment = %v, want only the egg variables", env)
+	}
+}
+
+func TestImportResume(t *testing.T) {
+	p, app := newPanel(t, targetResources())
+	i := &Importer{App: app, Mapping: newMapping()}
+	if err := i.Run(context.Background(), sourceSnapshot()); err != nil {
+		t.Fatal(err)
+	}
+
+	changes := len(p.changes)
+	mapping := *i.Mapping
+
+	if err := i.Run(context.Background(), sourceSnapshot()); err != nil {
+		t.Fatal(err)
+	}
+	if len(p.changes) != changes {
+		t.Errorf("second import made changes %q", p.changes[changes:])
+	}
+	if !reflect.DeepEqual(*i.Mapping, mapping) {
+		t.Errorf("second import changed the mapping to %+v", i.Mapping)
+	}
+}
+
+func TestImportResumePartial(t *testing.T) {
+	p, app := newPanel(t, targetResources())
+
+	// a previous import failed after creating the users
+	m := newMapping()
+	m.Locations = map[int]int{1: 10, 2: 11}
+	m.Nodes = map[int]int{1: 20}
+	m.Allocations = map[int]int{100: 200, 101: 201, 102: 202, 103: 203}
+	m.Users = map[int]int{1: 30, 2: 31}
+	m.Eggs = map[int]int{5: 15}
+
+	i := &Importer{App: app, Mapping: m}
+	if err := i.Run(context.Background(), sourceSnapshot()); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"POST /api/application/servers"}; !reflect.DeepEqual(p.changes, want) {
+		t.Errorf("changes = %q, want %q", p.changes, want)
+	}
+
+	server := p.bodies["/api/application/servers"][0]
+	if a, _ := server["allocation"].(map[string]interface{}); server["user"] != 30.0 || a["default"] != 201.0 {
+		t.Errorf("server = %v, want the user and allocation from the mapping", server)
+	}
+}
+
+func TestImportServerExternalID(t *testing.T) {
+	resources := targetResources()
+	resources["/api/application/servers"] = []map[string]interface{}{{"id": 50, "name": "survival", "external_id": "srv-1"}}
+	p, app := newPanel(t, resources)
+
+	s := sourceSnapshot()
+	s.Servers[0].ExternalID = "srv-1"
+
+	i := &Importer{App: app, Mapping: newMapping()}
+	if err := i.Run(context.Background(), s); err != nil {
+		t.Fatal(err)
+	}
+
+	if i.Mapping.Servers[1] != 50 {
+		t.Errorf("server mapped to %d, want 50", i.Mapping.Servers[1])
+	}
+	if len(p.bodies["/api/application/servers"]) != 0 {
+		t.Error("server was created, want it matched by external id")
+	}
+}
+
+func TestImportUsernameConflict(t *testing.T) {
+	resources := targetResources()
+	resources["/api/application/users"] = []map[string]interface{}{
+		{"id": 30, "username": "alice", "email": "alice@other.example.com"},
+	}
+	p, app := newPanel(t, resources)
+
+	i := &Importer{App: app, Mapping: newMapping()}
+	err := i.Run(context.Background(), sourceSnapshot())
+	if err == nil || !strings.Contains(err.Error(), "users section of the mapping file") {
+		t.Fatalf("Run() error = %v, want a username conflict", err)
+	}
+
+	if _, ok := i.Mapping.Users[1]; ok {
+		t.Error("user was mapped to the user with the same username")
+	}
+	if len(p.bodies["/api/application/users"]) != 0 || len(p.bodies["/api/application/servers"]) != 0 {
+		t.Errorf("changes = %q, want no users or servers created", p.changes)
+	}
+
+	// the pair can be mapped by hand
+	i.Mapping.Users[1] = 30
+	if err = i.Run(context.Background(), sourceSnapshot()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMapEggs(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping map[int]int
+		eggs    []*ptero.Egg
+		want    map[int]int
+		err     string
+	}{
+		{
+			name: "matched by name",
+			eggs: []*ptero.Egg{{ID: 5, Name: "Paper", Nest: 1}},
+			want: map[int]int{5: 15},
+		},
+		{
+			name: "missing",
+			eggs: []*ptero.Egg{{ID: 5, Name: "Forge", Nest: 1}},
+			want: map[int]int{},
+			err:  "Minecraft/Forge (id 5)",
+		},
+		{
+			name:    "mapped by hand",
+			mapping: map[int]int{5: 99},
+			eggs:    []*ptero.Egg{{ID: 5, Name: "Forge", Nest: 1}},
+			want:    map[int]int{5: 99},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, app := newPanel(t, targetResources())
+
+			s := sourceSnapshot()
+			s.Nests[0].Eggs = tt.eggs
+
+			m := newMapping()
+			if tt.mapping != nil {
+				m.Eggs = tt.mapping
+			}
+
+			i := &Importer{App: app, Mapping: m}
+			err := i.mapEggs(context.Background(), s)
+			if tt.err == "" && err != nil {
+				t.Fatal(err)
+			}
+			if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Fatalf("mapEggs() error = %v, want %s", err, tt.err)
+			}
+
+			if !reflect.DeepEqual(m.Eggs, tt.want) {
+				t.Errorf("eggs = %v, want %v", m.Eggs, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pteropackages/soar/ptero"
+	"gopkg.in/yaml.v3"
+)
+
+// Version is the version of the snapshot and mapping files. It is bumped when the
+// format changes in a way that older versions can't read.
+const Version = 1
+
+type Node struct {
+	*ptero.Node
+	Allocations []*ptero.Allocation `json:"allocations"`
+}
+
+type Nest struct {
+	*ptero.Nest
+	Eggs []*ptero.Egg `json:"eggs"`
+}
+
+// Snapshot is the state of a panel that can be exported and imported.
+type Snapshot struct {
+	Locations []*ptero.Location
+	Nodes     []*Node
+	Users     []*ptero.User
+	Nests     []*Nest
+	Servers   []*ptero.Server
+}
+
+func (s *Snapshot) files() map[string]interface{} {
+	return map[string]interface{}{
+		"locations": &s.Locations,
+		"nodes":     &s.Nodes,
+		"users":     &s.Users,
+		"nests":     &s.Nests,
+		"servers":   &s.Servers,
+	}
+}
+
+type file struct {
+	Version int             `json:"version"`
+	Data    json.RawMessage `json:"data"`
+}
+
+func Export(ctx context.Context, app *ptero.Application) (*Snapshot, error) {
+	s := &Snapshot{}
+	all := &ptero.ListOptions{All: true}
+	var err error
+
+	if s.Locations, _, err = app.ListLocations(ctx, all); err != nil {
+		return nil, err
+	}
+
+	nodes, _, err := app.ListNodes(ctx, all)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range nodes {
+		allocations, _, err := app.ListNodeAllocations(ctx, n.ID, all)
+		if err != nil {
+			return nil, err
+		}
+		s.Nodes = append(s.Nodes, &Node{Node: n, Allocations: allocations})
+	}
+
+	if s.Users, _, err = app.ListUsers(ctx, all); err != nil {
+		return nil, err
+	}
+
+	nests, _, err := app.ListNests(ctx, all)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range nests {
+		eggs, _, err := app.ListNestEggs(ctx, n.ID, all)
+		if err != nil {
+			return nil, err
+		}
+		s.Nests = append(s.Nests, &Nest{Nest: n, Eggs: eggs})
+	}
+
+	if s.Servers, _, err = app.ListServers(ctx, all); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Save writes each section of the snapshot to a separate file in the directory,
+// in either the json or yaml format.
+func (s *Snapshot) Save(dir, format string) error {
+	if format != "json" && format != "yaml" {
+		return fmt.Errorf("unknown format '%s' (must be json or yaml)", format)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	for name, data := range s.files() {
+		path := filepath.Join(dir, name+".json")
+		if format == "yaml" {
+			path = filepath.Join(dir, name+".yml")
+		}
+
+		if err := writeFile(path, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func Load(dir string) (*Snapshot, error) {
+	s := &Snapshot{}
+
+	for name, data := range s.files() {
+		if err := readFile(dir, name, data); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+func writeFile(path string, data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	buf, err := json.MarshalIndent(file{Version: Version, Data: raw}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if filepath.Ext(path) == ".yml" {
+		// json is valid yaml, so this keeps integers from being converted to floats
+		var node yaml.Node
+		if err = yaml.Unmarshal(buf, &node); err != nil {
+			return err
+		}
+		clearStyle(&node)
+
+		var out bytes.Buffer
+		enc := yaml.NewEncoder(&out)
+		enc.SetIndent(2)
+		if err = enc.Encode(&node); err != nil {
+			return err
+		}
+		buf = out.Bytes()
+	}
+
+	return os.WriteFile(path, buf, 0o644)
+}
+
+func clearStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, n := range node.Content {
+		clearStyle(n)
+	}
+}
+
+func readFile(dir, name string, out interface{}) error {
+	var buf []byte
+	var err error
+
+	for _, ext := range []string{".yml", ".yaml", ".json"} {
+		if buf, err = os.ReadFile(filepath.Join(dir, name+ext)); !errors.Is(err, os.ErrNotExist) {
+			break
+		}
+	}
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("no %s file found in '%s'", name, dir)
+		}
+
+		return err
+	}
+
+	var data interface{}
+	if err = yaml.Unmarshal(buf, &data); err != nil {
+		return fmt.Errorf("failed to parse %s file: %v", name, err)
+	}
+
+	if buf, err = json.Marshal(data); err != nil {
+		return err
+	}
+
+	var f file
+	if err = json.Unmarshal(buf, &f); err != nil {
+		return fmt.Errorf("failed to parse %s file: %v", name, err)
+	}
+
+	if f.Version != Version {
+		return fmt.Errorf("unsupported %s file version %d (expected %d)", name, f.Version, Version)
+	}
+
+	if err = json.Unmarshal(f.Data, out); err != nil {
+		return fmt.Errorf("failed to parse %s file: %v", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package ptero
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+)
+
+type Account struct {
+	ID        int    `json:"id"`
+	Admin     bool   `json:"admin"`
+	Username  string `json:"username"`
+	Email     string `json:"email"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Language  string `json:"language"`
+}
+
+type PermissionGroup struct {
+	Description string            `json:"description"`
+	Keys        map[string]string `json:"keys"`
+}
+
+type Permissions struct {
+	Permissions map[string]*PermissionGroup `json:"permissions"`
+}
+
+type TwoFactorData struct {
+	ImageURLData string `json:"image_url_data"`
+	Secret       string `json:"secret"`
+}
+
+type RecoveryTokens struct {
+	Tokens []string `json:"tokens"`
+}
+
+type Activity struct {
+	ID                    string                 `json:"id"`
+	Batch                 string                 `json:"batch"`
+	Event                 string                 `json:"event"`
+	IsAPI                 bool                   `json:"is_api"`
+	IP                    string                 `json:"ip"`
+	Description           string                 `json:"description"`
+	Properties            map[string]interface{} `json:"properties"`
+	HasAdditionalMetadata bool                   `json:"has_additional_metadata"`
+	Timestamp             string                 `json:"timestamp"`
+}
+
+type APIKey struct {
+	Identifier  string   `json:"identifier"`
+	Description string   `json:"description"`
+	AllowedIPs  []string `json:"allowed_ips"`
+	LastUsedAt  string   `json:"last_used_at"`
+	CreatedAt   string   `json:"created_at"`
+	Token       string   `json:"token,omitempty"`
+}
+
+type SSHKey struct {
+	Name        string `json:"name"`
+	Fingerprint string `json:"fingerprint"`
+	PublicKey   string `json:"public_key"`
+	CreatedAt   string `json:"created_at"`
+}
+
+func (c *Client) GetAccount(ctx context.Context) (*Account, error) {
+	var account Account
+	if err := c.item(ctx, "GET", "/api/client/account", nil, &account); err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+func (c *Client) GetPermissions(ctx context.Context) (*Permissions, error) {
+	var perms Permissions
+	if err := c.item(ctx, "GET", "/api/client/permissions", nil, &perms); err != nil {
+		return nil, err
+	}
+
+	return &perms, nil
+}
+
+func (c *Client) GetTwoFactor(ctx context.Context) (*TwoFactorData, error) {
+	res, err := c.raw(ctx, "GET", "/api/client/account/two-factor", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var model struct {
+		Data *TwoFactorData `json:"data"`
+	}
+	if err = json.Unmarshal(res, &model); err != nil {
+		return nil, err
+	}
+
+	model.Data.ImageURLData, err = url.PathUnescape(model.Data.ImageURLData)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.Data, nil
+}
+
+func (c *Client) EnableTwoFactor(ctx context.Context, code, password string) (*RecoveryTokens, error) {
+	var tokens RecoveryTokens
+	body := map[string]string{"code": code, "password": password}
+	if err := c.item(ctx, "POST", "/api/client/account/two-factor", body, &tokens); err != nil {
+		return nil, err
+	}
+
+	return &tokens, nil
+}
+
+func (c *Client) DisableTwoFactor(ctx context.Context, password string) error {
+	body := map[string]string{"password": password}
+
+	return c.item(ctx, "DELETE", "/api/client/account/two-factor", body, nil)
+}
+
+func (c *Client) ListAccountActivity(ctx context.Context, opts *ListOptions) ([]*Activity, *Meta, error) {
+	var activity []*Activity
+	meta, err := c.list(ctx, "/api/client/account/activity", opts, &activity)
+
+	return activity, meta, err
+}
+
+func (c *Client) ListAPIKeys(ctx context.Context) ([]*APIKey, error) {
+	var keys []*APIKey
+	_, err := c.list(ctx, "/api/client/account/api-keys", nil, &keys)
+
+	return keys, err
+}
+
+func (c *Client) DeleteAPIKey(ctx context.Context, id string) error {
+	return c.item(ctx, "DELETE", "/api/client/account/api-keys/"+id, nil, nil)
+}
+
+func (c *Client) UpdateEmail(ctx context.Context, email, password string) error {
+	body := map[string]string{"email": email, "password": password}
+
+	return c.item(ctx, "PUT", "/api/client/account/email", body, nil)
+}
+
+func (c *Client) UpdatePassword(ctx context.Context, current, password string) error {
+	body := map[string]string{
+		"current_password":      current,
+		"password":              password,
+		"password_confirmation": password,
+	}
+
+	return c.item(ctx, "PUT", "/api/client/account/password", body, nil)
+}
+
+func (c *Client) CreateAPIKey(ctx context.Context, description string, allowedIPs []string) (*APIKey, error) {
+	if allowedIPs == nil {
+		allowedIPs = []string{}
+	}
+
+	body := map[string]interface{}{"description": description, "allowed_ips": allowedIPs}
+	res, err := c.raw(ctx, "POST", "/api/client/account/api-keys", body)
+	if err != nil {
+		return nil, err
+	}
+
+	var model struct {
+		A *APIKey `json:"attributes"`
+		M struct {
+			Secret string `json:"secret_token"`
+		} `json:"meta"`
+	}
+	if err = json.Unmarshal(res, &model); err != nil {
+		return nil, err
+	}
+
+	model.A.Token = model.A.Identifier + model.M.Secret
+
+	return model.A, nil
+}
+
+func (c *Client) ListSSHKeys(ctx context.Context) ([]*SSHKey, error) {
+	var keys []*SSHKey
+	_, err := c.list(ctx, "/api/client/account/ssh-keys", nil, &keys)
+
+	return keys, err
+}
+
+func (c *Client) CreateSSHKey(ctx context.Context, name, key string) (*SSHKey, error) {
+	var k SSHKey
+	body := map[string]string{"name": name, "public_key": key}
+	if err := c.item(ctx, "POST", "/api/client/account/ssh-keys", body, &k); err != nil {
+		return nil, err
+	}
+
+	return &k, nil
+}
+
+func (c *Client) DeleteSSHKey(ctx context.Context, fingerprint string) error {
+	body := map[string]string{"fingerprint": fingerprint}
+
+	return c.item(ctx, "POST", "/api/client/account/ssh-keys/remove", body, nil)
+}
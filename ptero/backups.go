@@ -0,0 +1,77 @@
+package ptero
+
+import (
+	"context"
+	"fmt"
+)
+
+type Backup struct {
+	UUID         string   `json:"uuid"`
+	IsSuccessful bool     `json:"is_successful"`
+	IsLocked     bool     `json:"is_locked"`
+	Name         string   `json:"name"`
+	IgnoredFiles []string `json:"ignored_files"`
+	Checksum     *string  `json:"checksum"`
+	Bytes        int64    `json:"bytes"`
+	CreatedAt    string   `json:"created_at"`
+	CompletedAt  *string  `json:"completed_at"`
+}
+
+// Version identifies the backup for resuming downloads.
+func (b *Backup) Version() string {
+	return fmt.Sprintf("%d %s", b.Bytes, b.UUID)
+}
+
+type CreateBackupDescriptor struct {
+	Name     string `json:"name,omitempty"`
+	Ignored  string `json:"ignored,omitempty"`
+	IsLocked bool   `json:"is_locked"`
+}
+
+func (c *Client) ListBackups(ctx context.Context, id string, opts *ListOptions) ([]*Backup, *Meta, error) {
+	var backups []*Backup
+	meta, err := c.list(ctx, "/api/client/servers/"+id+"/backups", opts, &backups)
+
+	return backups, meta, err
+}
+
+func (c *Client) GetBackup(ctx context.Context, id, uuid string) (*Backup, error) {
+	var backup Backup
+	if err := c.item(ctx, "GET", "/api/client/servers/"+id+"/backups/"+uuid, nil, &backup); err != nil {
+		return nil, err
+	}
+
+	return &backup, nil
+}
+
+func (c *Client) CreateBackup(ctx context.Context, id string, fields CreateBackupDescriptor) (*Backup, error) {
+	var backup Backup
+	if err := c.item(ctx, "POST", "/api/client/servers/"+id+"/backups", fields, &backup); err != nil {
+		return nil, err
+	}
+
+	return &backup, nil
+}
+
+func (c *Client) GetBackupDownloadURL(ctx context.Context, id, uuid string) (string, error) {
+	return c.signedURL(ctx, "/api/client/servers/"+id+"/backups/"+uuid+"/download")
+}
+
+func (c *Client) ToggleBackupLock(ctx context.Context, id, uuid string) (*Backup, error) {
+	var backup Backup
+	if err := c.item(ctx, "POST", "/api/client/servers/"+id+"/backups/"+uuid+"/lock", nil, &backup); err != nil {
+		return nil, err
+	}
+
+	return &backup, nil
+}
+
+func (c *Client) RestoreBackup(ctx context.Context, id, uuid string, truncate bool) error {
+	body := map[string]bool{"truncate": truncate}
+
+	return c.item(ctx, "POST", "/api/client/servers/"+id+"/backups/"+uuid+"/restore", body, nil)
+}
+
+func (c *Client) DeleteBackup(ctx context.Context, id, uuid string) error {
+	return c.item(ctx, "DELETE", "/api/client/servers/"+id+"/backups/"+uuid, nil, nil)
+}
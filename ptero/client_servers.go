@@ -0,0 +1,130 @@
+package ptero
+
+import (
+	"context"
+	"encoding/json"
+)
+
+type ClientServer struct {
+	ServerOwner            bool   `json:"server_owner"`
+	Identifier             string `json:"identifier"`
+	InternalID             int    `json:"internal_id"`
+	UUID                   string `json:"uuid"`
+	Name                   string `json:"name"`
+	Node                   string `json:"node"`
+	IsNodeUnderMaintenance bool   `json:"is_node_under_maintenance"`
+	SFTPDetails            struct {
+		IP   string `json:"ip"`
+		Port int    `json:"port"`
+	} `json:"sftp_details"`
+	Description    string        `json:"description"`
+	Limits         Limits        `json:"limits"`
+	Invocation     string        `json:"invocation"`
+	DockerImage    string        `json:"docker_image"`
+	EggFeatures    []string      `json:"egg_features"`
+	FeatureLimits  FeatureLimits `json:"feature_limits"`
+	Status         string        `json:"status"`
+	IsSuspended    bool          `json:"is_suspended"`
+	IsInstalling   bool          `json:"is_installing"`
+	IsTransferring bool          `json:"is_transferring"`
+}
+
+type WebSocketAuth struct {
+	Token  string `json:"token"`
+	Socket string `json:"socket"`
+}
+
+type Resources struct {
+	CurrentState string `json:"current_state"`
+	IsSuspended  bool   `json:"is_suspended"`
+	Resources    struct {
+		MemoryBytes    int64   `json:"memory_bytes"`
+		CPUAbsolute    float64 `json:"cpu_absolute"`
+		DiskBytes      int64   `json:"disk_bytes"`
+		NetworkRxBytes int64   `json:"network_rx_bytes"`
+		NetworkTxBytes int64   `json:"network_tx_bytes"`
+		Uptime         int64   `json:"uptime"`
+	} `json:"resources"`
+}
+
+type ServerResources struct {
+	Identifier string `json:"identifier"`
+	Name       string `json:"name,omitempty"`
+	Resources
+}
+
+func (c *Client) ListServers(ctx context.Context, opts *ListOptions) ([]*ClientServer, *Meta, error) {
+	var servers []*ClientServer
+	meta, err := c.list(ctx, "/api/client", opts, &servers)
+
+	return servers, meta, err
+}
+
+func (c *Client) GetServer(ctx context.Context, id string) (*ClientServer, error) {
+	var server ClientServer
+	if err := c.item(ctx, "GET", "/api/client/servers/"+id, nil, &server); err != nil {
+		return nil, err
+	}
+
+	return &server, nil
+}
+
+func (c *Client) GetServerWebSocket(ctx context.Context, id string) (*WebSocketAuth, error) {
+	res, err := c.raw(ctx, "GET", "/api/client/servers/"+id+"/websocket", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var model struct {
+		Data *WebSocketAuth `json:"data"`
+	}
+	if err = json.Unmarshal(res, &model); err != nil {
+		return nil, err
+	}
+
+	return model.Data, nil
+}
+
+func (c *Client) GetServerResources(ctx context.Context, id string) (*Resources, error) {
+	var res Resources
+	if err := c.item(ctx, "GET", "/api/client/servers/"+id+"/resources", nil, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (c *Client) ListServerActivity(ctx context.Context, id string, opts *ListOptions) ([]*Activity, *Meta, error) {
+	var activity []*Activity
+	meta, err := c.list(ctx, "/api/client/servers/"+id+"/activity", opts, &activity)
+
+	return activity, meta, err
+}
+
+func (c *Client) SendServerCommand(ctx context.Context, id, command string) error {
+	body := map[string]string{"command": command}
+
+	return c.item(ctx, "POST", "/api/client/servers/"+id+"/command", body, nil)
+}
+
+func (c *Client) SetServerPowerState(ctx context.Context, id, state string) error {
+	body := map[string]string{"signal": state}
+
+	return c.item(ctx, "POST", "/api/client/servers/"+id+"/power", body, nil)
+}
+
+func (c *Client) RenameServer(ctx context.Context, id, name string) error {
+	body := map[string]string{"name": name}
+
+	return c.item(ctx, "POST", "/api/client/servers/"+id+"/settings/rename", body, nil)
+}
+
+func (c *Client) ReinstallServer(ctx context.Context, id string) error {
+	return c.item(ctx, "POST", "/api/client/servers/"+id+"/settings/reinstall", nil, nil)
+}
+
+func (c *Client) SetDockerImage(ctx context.Context, id, image string) error {
+	body := map[string]string{"docker_image": image}
+
+	return c.item(ctx, "PUT", "/api/client/servers/"+id+"/settings/docker-image", body, nil)
+}
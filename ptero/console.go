@@ -0,0 +1,135 @@
+package ptero
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+type ConsoleEvent struct {
+	Event string   `json:"event"`
+	Args  []string `json:"args,omitempty"`
+}
+
+type ConsoleStats struct {
+	State            string  `json:"state"`
+	MemoryBytes      int64   `json:"memory_bytes"`
+	MemoryLimitBytes int64   `json:"memory_limit_bytes"`
+	CPUAbsolute      float64 `json:"cpu_absolute"`
+	DiskBytes        int64   `json:"disk_bytes"`
+	Network          struct {
+		RxBytes int64 `json:"rx_bytes"`
+		TxBytes int64 `json:"tx_bytes"`
+	} `json:"network"`
+	Uptime int64 `json:"uptime"`
+}
+
+func (e *ConsoleEvent) Stats() (*ConsoleStats, error) {
+	if len(e.Args) == 0 {
+		return nil, errors.New("missing stats event arguments")
+	}
+
+	var stats ConsoleStats
+	if err := json.Unmarshal([]byte(e.Args[0]), &stats); err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+type Console struct {
+	client *Client
+	id     string
+	conn   *websocket.Conn
+	mu     sync.Mutex
+}
+
+// ConnectConsole opens an authenticated websocket connection to the server console.
+func (c *Client) ConnectConsole(ctx context.Context, id string) (*Console, error) {
+	auth, err := c.GetServerWebSocket(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	header := http.Header{}
+	header.Set("Origin", strings.TrimSuffix(c.http.URL(), "/"))
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, auth.Socket, header)
+	if err != nil {
+		return nil, err
+	}
+
+	console := &Console{client: c, id: id, conn: conn}
+	if err = console.Send("auth", auth.Token); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return console, nil
+}
+
+func (c *Console) Send(event string, args ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.conn.WriteJSON(ConsoleEvent{Event: event, Args: args})
+}
+
+func (c *Console) SendCommand(command string) error {
+	return c.Send("send command", command)
+}
+
+func (c *Console) SetPowerState(state string) error {
+	return c.Send("set state", state)
+}
+
+// Read waits for the next console event, authenticating again when the token is
+// about to expire. The connection is closed if the context is done while waiting.
+func (c *Console) Read(ctx context.Context) (*ConsoleEvent, error) {
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.conn.Close()
+		case <-done:
+		}
+	}()
+
+	var event ConsoleEvent
+	if err := c.conn.ReadJSON(&event); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		return nil, err
+	}
+
+	switch event.Event {
+	case "token expiring", "token expired":
+		auth, err := c.client.GetServerWebSocket(ctx, c.id)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = c.Send("auth", auth.Token); err != nil {
+			return nil, err
+		}
+	}
+
+	return &event, nil
+}
+
+func (c *Console) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	return c.conn.Close()
+}
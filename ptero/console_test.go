@@ -0,0 +1,145 @@
+package ptero
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// newConsole starts a panel that issues numbered websocket tokens and passes each
+// console connection to handle, and returns a console connected to it.
+func newConsole(t *testing.T, handle func(conn *websocket.Conn)) *Console {
+	var mu sync.Mutex
+	tokens := 0
+	upgrader := websocket.Upgrader{}
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/client/servers/s1/websocket":
+			mu.Lock()
+			tokens++
+			token := fmt.Sprintf("token%d", tokens)
+			mu.Unlock()
+
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": WebSocketAuth{Token: token, Socket: "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws"},
+			})
+
+		case "/ws":
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				t.Errorf("failed to upgrade: %v", err)
+				return
+			}
+			defer conn.Close()
+
+			handle(conn)
+
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	console, err := NewClientWithKey(srv.URL, "key").ConnectConsole(context.Background(), "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { console.Close() })
+
+	return console
+}
+
+func expectAuth(conn *websocket.Conn, token string) error {
+	var event ConsoleEvent
+	if err := conn.ReadJSON(&event); err != nil {
+		return err
+	}
+	if event.Event != "auth" || len(event.Args) != 1 || event.Args[0] != token {
+		return fmt.Errorf("got event %+v, want auth with %s", event, token)
+	}
+
+	return nil
+}
+
+func TestConsoleRead(t *testing.T) {
+	errs := make(chan error, 1)
+	console := newConsole(t, func(conn *websocket.Conn) {
+		errs <- func() error {
+			if err := expectAuth(conn, "token1"); err != nil {
+				return err
+			}
+			if err := conn.WriteJSON(ConsoleEvent{Event: "token expiring"}); err != nil {
+				return err
+			}
+			if err := expectAuth(conn, "token2"); err != nil {
+				return err
+			}
+
+			return conn.WriteJSON(ConsoleEvent{Event: "console output", Args: []string{"server started"}})
+		}()
+
+		// keep the connection open until the client closes it
+		conn.ReadMessage()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	event, err := console.Read(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Event != "token expiring" {
+		t.Errorf("Read() event = %s, want token expiring", event.Event)
+	}
+
+	event, err = console.Read(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Event != "console output" || len(event.Args) != 1 || event.Args[0] != "server started" {
+		t.Errorf("Read() = %+v, want console output", event)
+	}
+
+	if err = <-errs; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestConsoleReadContext(t *testing.T) {
+	console := newConsole(t, func(conn *websocket.Conn) {
+		// never send an event
+		conn.ReadMessage()
+		conn.ReadMessage()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	res := make(chan error, 1)
+	go func() {
+		_, err := console.Read(ctx)
+		res <- err
+	}()
+
+	select {
+	case err := <-res:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Read() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Read() did not return after the context was done")
+	}
+}
@@ -0,0 +1,101 @@
+package ptero
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+type Database struct {
+	ID   string `json:"id"`
+	Host struct {
+		Address string `json:"address"`
+		Port    int    `json:"port"`
+	} `json:"host"`
+	Name            string `json:"name"`
+	Username        string `json:"username"`
+	Password        string `json:"password,omitempty"`
+	ConnectionsFrom string `json:"connections_from"`
+	MaxConnections  int    `json:"max_connections"`
+}
+
+func (d *Database) UnmarshalJSON(data []byte) error {
+	type database Database
+	var model struct {
+		*database
+		R struct {
+			P struct {
+				A struct {
+					Password string `json:"password"`
+				} `json:"attributes"`
+			} `json:"password"`
+		} `json:"relationships"`
+	}
+	model.database = (*database)(d)
+
+	if err := json.Unmarshal(data, &model); err != nil {
+		return err
+	}
+
+	if model.R.P.A.Password != "" {
+		d.Password = model.R.P.A.Password
+	}
+
+	return nil
+}
+
+func (d *Database) ConnectionString(format string) (string, error) {
+	host := d.Host.Address + ":" + strconv.Itoa(d.Host.Port)
+
+	switch format {
+	case "jdbc":
+		return fmt.Sprintf("jdbc:mysql://%s/%s?user=%s&password=%s", host, d.Name,
+			url.QueryEscape(d.Username), url.QueryEscape(d.Password)), nil
+	case "mysql":
+		u := url.URL{Scheme: "mysql", User: url.UserPassword(d.Username, d.Password), Host: host, Path: "/" + d.Name}
+
+		return u.String(), nil
+	default:
+		return "", fmt.Errorf("unknown connection string format '%s' (must be one of: jdbc, mysql)", format)
+	}
+}
+
+type CreateDatabaseDescriptor struct {
+	Database string `json:"database" validate:"required,max=48"`
+	Remote   string `json:"remote" validate:"required"`
+}
+
+func (c *Client) ListDatabases(ctx context.Context, id string, opts *ListOptions) ([]*Database, error) {
+	var databases []*Database
+	_, err := c.list(ctx, "/api/client/servers/"+id+"/databases", opts, &databases)
+
+	return databases, err
+}
+
+func (c *Client) CreateDatabase(ctx context.Context, id string, fields CreateDatabaseDescriptor) (*Database, error) {
+	if err := validate.Struct(fields); err != nil {
+		return nil, err
+	}
+
+	var database Database
+	if err := c.item(ctx, "POST", "/api/client/servers/"+id+"/databases", fields, &database); err != nil {
+		return nil, err
+	}
+
+	return &database, nil
+}
+
+func (c *Client) RotateDatabasePassword(ctx context.Context, id, database string) (*Database, error) {
+	var d Database
+	if err := c.item(ctx, "POST", "/api/client/servers/"+id+"/databases/"+database+"/rotate-password", nil, &d); err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+func (c *Client) DeleteDatabase(ctx context.Context, id, database string) error {
+	return c.item(ctx, "DELETE", "/api/client/servers/"+id+"/databases/"+database, nil, nil)
+}
@@ -0,0 +1,396 @@
+package ptero
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pteropackages/soar/http"
+)
+
+type File struct {
+	Name       string `json:"name"`
+	Size       int64  `json:"size"`
+	Mode       string `json:"mode"`
+	ModeBits   string `json:"mode_bits"`
+	MimeType   string `json:"mimetype"`
+	IsFile     bool   `json:"is_file"`
+	IsSymlink  bool   `json:"is_symlink"`
+	CreatedAt  string `json:"created_at"`
+	ModifiedAt string `json:"modified_at"`
+}
+
+func (f *File) IsDir() bool {
+	return f.MimeType == "inode/directory"
+}
+
+// Version identifies this version of the file for resuming downloads.
+func (f *File) Version() string {
+	return fmt.Sprintf("%d %s", f.Size, f.ModifiedAt)
+}
+
+type PullFileDescriptor struct {
+	URL        string `json:"url"`
+	Directory  string `json:"directory,omitempty"`
+	Filename   string `json:"filename,omitempty"`
+	UseHeader  bool   `json:"use_header"`
+	Foreground bool   `json:"foreground"`
+}
+
+// Progress receives the bytes of a file transfer as they are written.
+type Progress interface {
+	io.Writer
+	Start(offset, total int64)
+}
+
+func (c *Client) ListFiles(ctx context.Context, id, dir string) ([]*File, error) {
+	var files []*File
+	_, err := c.list(ctx, "/api/client/servers/"+id+"/files/list?directory="+url.QueryEscape(dir), nil, &files)
+
+	return files, err
+}
+
+// GetFile returns the file at the path by listing its directory.
+func (c *Client) GetFile(ctx context.Context, id, p string) (*File, error) {
+	files, err := c.ListFiles(ctx, id, path.Dir(p))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		if file.Name == path.Base(p) {
+			return file, nil
+		}
+	}
+
+	return nil, fmt.Errorf("file '%s' not found", p)
+}
+
+func (c *Client) WalkFiles(ctx context.Context, id, root string, fn func(dir string, file *File) error) error {
+	files, err := c.ListFiles(ctx, id, root)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if err = fn(root, file); err != nil {
+			return err
+		}
+
+		if file.IsDir() && !file.IsSymlink {
+			if err = c.WalkFiles(ctx, id, path.Join(root, file.Name), fn); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) GetFileContents(ctx context.Context, id, path string) ([]byte, error) {
+	req := c.http.Request("GET", "/api/client/servers/"+id+"/files/contents?file="+url.QueryEscape(path), nil)
+	req.Header.Set("Accept", "text/plain")
+
+	return c.http.Do(req.WithContext(ctx))
+}
+
+func (c *Client) signedURL(ctx context.Context, path string) (string, error) {
+	var model struct {
+		URL string `json:"url"`
+	}
+	if err := c.item(ctx, "GET", path, nil, &model); err != nil {
+		return "", err
+	}
+
+	return model.URL, nil
+}
+
+func (c *Client) GetFileDownloadURL(ctx context.Context, id, path string) (string, error) {
+	return c.signedURL(ctx, "/api/client/servers/"+id+"/files/download?file="+url.QueryEscape(path))
+}
+
+// partialDownload describes the remote file that a partial download was started
+// for. It is saved next to the partial file so that a download is only resumed
+// from the same version of the file.
+type partialDownload struct {
+	Version   string `json:"version"`
+	Size      int64  `json:"size"`
+	Validator string `json:"validator,omitempty"`
+}
+
+// readPartial returns the partial download for dest and the number of bytes that
+// were downloaded, or nil if there is none for this version of the remote file.
+func readPartial(dest, version string) (*partialDownload, int64) {
+	if version == "" {
+		return nil, 0
+	}
+
+	info, err := os.Stat(dest + ".part")
+	if err != nil || info.Size() == 0 {
+		return nil, 0
+	}
+
+	buf, err := os.ReadFile(dest + ".part.json")
+	if err != nil {
+		return nil, 0
+	}
+
+	var part partialDownload
+	if err = json.Unmarshal(buf, &part); err != nil || part.Version != version || info.Size() >= part.Size {
+		return nil, 0
+	}
+
+	return &part, info.Size()
+}
+
+// CanResumeDownload reports whether DownloadFile will resume a partial download of
+// this version of the remote file to dest.
+func CanResumeDownload(dest, version string) bool {
+	part, _ := readPartial(dest, version)
+	return part != nil
+}
+
+// parseContentRange returns the first byte and the total size from a
+// Content-Range header (e.g. "bytes 100-199/200").
+func parseContentRange(header string) (int64, int64, bool) {
+	var start, end, total int64
+	if _, err := fmt.Sscanf(header, "bytes %d-%d/%d", &start, &end, &total); err != nil {
+		return 0, 0, false
+	}
+
+	return start, total, true
+}
+
+// rangeValidator returns the value to send with If-Range when resuming, so that
+// the full file is sent if it has changed. Weak ETags can't be used for ranges.
+func rangeValidator(etag, modified string) string {
+	if etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+
+	return modified
+}
+
+// DownloadFile streams the file at location to dest through a temporary file that
+// is renamed once the download completes. The version identifies the remote file
+// (see File.Version); if a partial download of the same version exists, it is
+// resumed with a range request. An empty version always starts a new download.
+func (c *Client) DownloadFile(ctx context.Context, location, dest, version string, progress Progress) error {
+	temp := dest + ".part"
+	meta := temp + ".json"
+
+	part, offset := readPartial(dest, version)
+
+	req := http.Request("GET", location, nil)
+	req.Header.Set("Accept", "application/octet-stream")
+	if part != nil {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if part.Validator != "" {
+			req.Header.Set("If-Range", part.Validator)
+		}
+	}
+
+	res, err := c.http.Stream(req.WithContext(ctx))
+	if err != nil {
+		var e *http.APIError
+		if part != nil && errors.As(err, &e) && e.Status == 416 {
+			os.Remove(temp)
+			os.Remove(meta)
+			return errors.New("the partial download no longer matches the file and was removed, try again")
+		}
+
+		return err
+	}
+	defer res.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if res.StatusCode == 206 {
+		start, total, ok := parseContentRange(res.Header.Get("Content-Range"))
+		if part == nil || !ok || start != offset || total != part.Size {
+			os.Remove(temp)
+			os.Remove(meta)
+			return fmt.Errorf("the partial download no longer matches the file (content range '%s') and was removed, try again", res.Header.Get("Content-Range"))
+		}
+	} else {
+		offset = 0
+		flags |= os.O_TRUNC
+
+		// the download can only be resumed if the size of the file is known
+		os.Remove(meta)
+		if version != "" && res.ContentLength > 0 {
+			buf, _ := json.Marshal(partialDownload{
+				Version:   version,
+				Size:      res.ContentLength,
+				Validator: rangeValidator(res.Header.Get("ETag"), res.Header.Get("Last-Modified")),
+			})
+			if err = os.WriteFile(meta, buf, 0o644); err != nil {
+				return err
+			}
+		}
+	}
+
+	file, err := os.OpenFile(temp, flags, 0o644)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = file
+	if progress != nil {
+		total := res.ContentLength
+		if total >= 0 {
+			total += offset
+		}
+		progress.Start(offset, total)
+		w = io.MultiWriter(file, progress)
+	}
+
+	_, err = io.Copy(w, res.Body)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	os.Remove(meta)
+	return os.Rename(temp, dest)
+}
+
+func (c *Client) RenameFile(ctx context.Context, id, root, from, to string) error {
+	files := []map[string]string{{"from": from, "to": to}}
+	body := map[string]interface{}{"root": root, "files": files}
+
+	return c.item(ctx, "PUT", "/api/client/servers/"+id+"/files/rename", body, nil)
+}
+
+func (c *Client) CopyFile(ctx context.Context, id, location string) error {
+	body := map[string]string{"location": location}
+
+	return c.item(ctx, "POST", "/api/client/servers/"+id+"/files/copy", body, nil)
+}
+
+func (c *Client) WriteFile(ctx context.Context, id, path string, content []byte) error {
+	body := bytes.Buffer{}
+	body.Write(content)
+
+	req := c.http.Request("POST", "/api/client/servers/"+id+"/files/write?file="+url.QueryEscape(path), &body)
+	req.Header.Set("Content-Type", "text/plain")
+	_, err := c.http.Do(req.WithContext(ctx))
+
+	return err
+}
+
+func (c *Client) CompressFiles(ctx context.Context, id, root string, files []string) (*File, error) {
+	var file File
+	body := map[string]interface{}{"root": root, "files": files}
+	if err := c.item(ctx, "POST", "/api/client/servers/"+id+"/files/compress", body, &file); err != nil {
+		return nil, err
+	}
+
+	return &file, nil
+}
+
+func (c *Client) DecompressFile(ctx context.Context, id, root, file string) error {
+	body := map[string]string{"root": root, "file": file}
+
+	return c.item(ctx, "POST", "/api/client/servers/"+id+"/files/decompress", body, nil)
+}
+
+func (c *Client) DeleteFiles(ctx context.Context, id, root string, files []string) error {
+	body := map[string]interface{}{"root": root, "files": files}
+
+	return c.item(ctx, "POST", "/api/client/servers/"+id+"/files/delete", body, nil)
+}
+
+func (c *Client) CreateFolder(ctx context.Context, id, root, name string) error {
+	body := map[string]string{"root": root, "name": name}
+
+	return c.item(ctx, "POST", "/api/client/servers/"+id+"/files/create-folder", body, nil)
+}
+
+func (c *Client) ChmodFile(ctx context.Context, id, root, file string, mode int) error {
+	files := []map[string]interface{}{{"file": file, "mode": mode}}
+	body := map[string]interface{}{"root": root, "files": files}
+
+	return c.item(ctx, "POST", "/api/client/servers/"+id+"/files/chmod", body, nil)
+}
+
+func (c *Client) PullFile(ctx context.Context, id string, fields PullFileDescriptor) error {
+	return c.item(ctx, "POST", "/api/client/servers/"+id+"/files/pull", fields, nil)
+}
+
+func (c *Client) GetUploadURL(ctx context.Context, id string) (string, error) {
+	return c.signedURL(ctx, "/api/client/servers/"+id+"/files/upload")
+}
+
+func (c *Client) UploadFiles(ctx context.Context, location string, paths []string, progress Progress) error {
+	return c.UploadFilesTo(ctx, location, "", paths, progress)
+}
+
+// UploadFilesTo streams the files as a multipart body to the upload location, so
+// that they are not buffered into memory.
+func (c *Client) UploadFilesTo(ctx context.Context, location, dir string, paths []string, progress Progress) error {
+	if dir != "" {
+		location += "&directory=" + url.QueryEscape(dir)
+	}
+
+	var total int64
+	for _, name := range paths {
+		info, err := os.Stat(name)
+		if err != nil {
+			return err
+		}
+		total += info.Size()
+	}
+
+	if progress != nil {
+		progress.Start(0, total)
+	}
+
+	reader, pipe := io.Pipe()
+	writer := multipart.NewWriter(pipe)
+	go func() {
+		pipe.CloseWithError(writeParts(writer, paths, progress))
+	}()
+
+	req := http.StreamRequest("POST", location, reader)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	_, err := c.http.Do(req.WithContext(ctx))
+	reader.Close()
+
+	return err
+}
+
+func writeParts(writer *multipart.Writer, paths []string, progress Progress) error {
+	for _, name := range paths {
+		file, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+
+		var r io.Reader = file
+		if progress != nil {
+			r = io.TeeReader(file, progress)
+		}
+
+		part, err := writer.CreateFormFile("files", filepath.Base(name))
+		if err == nil {
+			_, err = io.Copy(part, r)
+		}
+		file.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
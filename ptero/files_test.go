@@ -0,0 +1,164 @@
+package ptero
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var fileContent = []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+
+// newFileServer serves the content with range support and records the Range
+// header of each request.
+func newFileServer(t *testing.T, content []byte, modified time.Time) (*httptest.Server, *[]string) {
+	var ranges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "file", modified, bytes.NewReader(content))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &ranges
+}
+
+func writePartial(t *testing.T, dest string, content []byte, part *partialDownload) {
+	if err := os.WriteFile(dest+".part", content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if part != nil {
+		buf, _ := json.Marshal(part)
+		if err := os.WriteFile(dest+".part.json", buf, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDownloadFile(t *testing.T) {
+	modified := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	validator := modified.Format(http.TimeFormat)
+	size := int64(len(fileContent))
+
+	tests := []struct {
+		name    string
+		partial []byte
+		part    *partialDownload
+		served  []byte
+		version string
+		want    string
+	}{
+		{
+			name:    "new",
+			version: "v1",
+		},
+		{
+			name:    "resume",
+			partial: fileContent[:10],
+			part:    &partialDownload{Version: "v1", Size: size, Validator: validator},
+			version: "v1",
+			want:    "bytes=10-",
+		},
+		{
+			name:    "different version",
+			partial: []byte("stale bytes"),
+			part:    &partialDownload{Version: "v0", Size: size, Validator: validator},
+			version: "v1",
+		},
+		{
+			name:    "missing info",
+			partial: []byte("stale bytes"),
+			version: "v1",
+		},
+		{
+			name:    "no version",
+			partial: fileContent[:10],
+			part:    &partialDownload{Version: "", Size: size},
+			version: "",
+		},
+		{
+			name:    "changed since",
+			partial: []byte("stale byte"),
+			part:    &partialDownload{Version: "v1", Size: size, Validator: "Sat, 01 Jan 2022 00:00:00 GMT"},
+			version: "v1",
+			want:    "bytes=10-",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, ranges := newFileServer(t, fileContent, modified)
+			dest := filepath.Join(t.TempDir(), "file")
+			if tt.partial != nil {
+				writePartial(t, dest, tt.partial, tt.part)
+			}
+
+			c := NewClientWithKey(srv.URL, "key")
+			if err := c.DownloadFile(context.Background(), srv.URL+"/download", dest, tt.version, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(*ranges) != 1 || (*ranges)[0] != tt.want {
+				t.Errorf("range headers = %q, want [%q]", *ranges, tt.want)
+			}
+
+			buf, err := os.ReadFile(dest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(buf, fileContent) {
+				t.Errorf("downloaded %q, want %q", buf, fileContent)
+			}
+
+			for _, name := range []string{dest + ".part", dest + ".part.json"} {
+				if _, err = os.Stat(name); err == nil {
+					t.Errorf("%s was not removed", filepath.Base(name))
+				}
+			}
+		})
+	}
+}
+
+func TestDownloadFileSizeChanged(t *testing.T) {
+	// the file on the server is larger than when the download was started
+	srv, _ := newFileServer(t, append(fileContent, "more"...), time.Time{})
+	dest := filepath.Join(t.TempDir(), "file")
+	writePartial(t, dest, fileContent[:10], &partialDownload{Version: "v1", Size: int64(len(fileContent))})
+
+	c := NewClientWithKey(srv.URL, "key")
+	if err := c.DownloadFile(context.Background(), srv.URL+"/download", dest, "v1", nil); err == nil {
+		t.Fatal("DownloadFile() error = nil, want a content range mismatch")
+	}
+
+	for _, name := range []string{dest, dest + ".part", dest + ".part.json"} {
+		if _, err := os.Stat(name); err == nil {
+			t.Errorf("%s exists, want it removed", filepath.Base(name))
+		}
+	}
+}
+
+func TestDownloadFileInterrupted(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "36")
+		w.Write(fileContent[:10])
+	}))
+	defer srv.Close()
+
+	dest := filepath.Join(t.TempDir(), "file")
+	c := NewClientWithKey(srv.URL, "key")
+	if err := c.DownloadFile(context.Background(), srv.URL+"/download", dest, "v1", nil); err == nil {
+		t.Fatal("DownloadFile() error = nil, want an unexpected EOF")
+	}
+
+	if !CanResumeDownload(dest, "v1") {
+		t.Error("CanResumeDownload() = false, want true after an interrupted download")
+	}
+	if CanResumeDownload(dest, "v2") {
+		t.Error("CanResumeDownload() = true for a different version")
+	}
+}
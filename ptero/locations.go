@@ -0,0 +1,57 @@
+package ptero
+
+import (
+	"context"
+	"fmt"
+)
+
+type Location struct {
+	ID        int    `json:"id"`
+	Short     string `json:"short"`
+	Long      string `json:"long"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type CreateLocationDescriptor struct {
+	Short string `json:"short"`
+	Long  string `json:"long"`
+}
+
+func (a *Application) ListLocations(ctx context.Context, opts *ListOptions) ([]*Location, *Meta, error) {
+	var locations []*Location
+	meta, err := a.list(ctx, "/api/application/locations", opts, &locations)
+
+	return locations, meta, err
+}
+
+func (a *Application) GetLocation(ctx context.Context, id int) (*Location, error) {
+	var location Location
+	if err := a.item(ctx, "GET", fmt.Sprintf("/api/application/locations/%d", id), nil, &location); err != nil {
+		return nil, err
+	}
+
+	return &location, nil
+}
+
+func (a *Application) CreateLocation(ctx context.Context, fields CreateLocationDescriptor) (*Location, error) {
+	var location Location
+	if err := a.item(ctx, "POST", "/api/application/locations", fields, &location); err != nil {
+		return nil, err
+	}
+
+	return &location, nil
+}
+
+func (a *Application) UpdateLocation(ctx context.Context, id int, fields CreateLocationDescriptor) (*Location, error) {
+	var location Location
+	if err := a.item(ctx, "PATCH", fmt.Sprintf("/api/application/locations/%d", id), fields, &location); err != nil {
+		return nil, err
+	}
+
+	return &location, nil
+}
+
+func (a *Application) DeleteLocation(ctx context.Context, id int) error {
+	return a.item(ctx, "DELETE", fmt.Sprintf("/api/application/locations/%d", id), nil, nil)
+}
@@ -0,0 +1,121 @@
+package ptero
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+type Nest struct {
+	ID          int    `json:"id"`
+	UUID        string `json:"uuid"`
+	Author      string `json:"author"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+type Egg struct {
+	ID           int               `json:"id"`
+	UUID         string            `json:"uuid"`
+	Name         string            `json:"name"`
+	Nest         int               `json:"nest"`
+	Author       string            `json:"author"`
+	Description  string            `json:"description"`
+	DockerImage  string            `json:"docker_image"`
+	DockerImages map[string]string `json:"docker_images"`
+	Config       struct {
+		Files        interface{} `json:"files"`
+		Startup      interface{} `json:"startup"`
+		Stop         string      `json:"stop"`
+		Logs         interface{} `json:"logs"`
+		FileDenylist []string    `json:"file_denylist"`
+		Extends      *int        `json:"extends"`
+	} `json:"config"`
+	Startup string `json:"startup"`
+	Script  struct {
+		Privileged bool   `json:"privileged"`
+		Install    string `json:"install"`
+		Entry      string `json:"entry"`
+		Container  string `json:"container"`
+		Extends    *int   `json:"extends"`
+	} `json:"script"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type EggVariable struct {
+	ID           int    `json:"id"`
+	EggID        int    `json:"egg_id"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	EnvVariable  string `json:"env_variable"`
+	DefaultValue string `json:"default_value"`
+	UserViewable bool   `json:"user_viewable"`
+	UserEditable bool   `json:"user_editable"`
+	Rules        string `json:"rules"`
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at"`
+}
+
+func (a *Application) ListNests(ctx context.Context, opts *ListOptions) ([]*Nest, *Meta, error) {
+	var nests []*Nest
+	meta, err := a.list(ctx, "/api/application/nests", opts, &nests)
+
+	return nests, meta, err
+}
+
+func (a *Application) GetNest(ctx context.Context, id int) (*Nest, error) {
+	var nest Nest
+	if err := a.item(ctx, "GET", fmt.Sprintf("/api/application/nests/%d", id), nil, &nest); err != nil {
+		return nil, err
+	}
+
+	return &nest, nil
+}
+
+func (a *Application) ListNestEggs(ctx context.Context, nest int, opts *ListOptions) ([]*Egg, *Meta, error) {
+	var eggs []*Egg
+	meta, err := a.list(ctx, fmt.Sprintf("/api/application/nests/%d/eggs", nest), opts, &eggs)
+
+	return eggs, meta, err
+}
+
+func (a *Application) GetNestEgg(ctx context.Context, nest, id int) (*Egg, error) {
+	var egg Egg
+	if err := a.item(ctx, "GET", fmt.Sprintf("/api/application/nests/%d/eggs/%d", nest, id), nil, &egg); err != nil {
+		return nil, err
+	}
+
+	return &egg, nil
+}
+
+func (a *Application) ListNestEggVariables(ctx context.Context, nest, id int) ([]*EggVariable, error) {
+	res, err := a.raw(ctx, "GET", fmt.Sprintf("/api/application/nests/%d/eggs/%d?include=variables", nest, id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var model struct {
+		A struct {
+			R struct {
+				V json.RawMessage `json:"variables"`
+			} `json:"relationships"`
+		} `json:"attributes"`
+	}
+	if err = json.Unmarshal(res, &model); err != nil {
+		return nil, err
+	}
+
+	var vars []*EggVariable
+	if len(model.A.R.V) == 0 {
+		return vars, nil
+	}
+
+	if _, err = decodeList(model.A.R.V, &vars); err != nil {
+		return nil, err
+	}
+
+	return vars, nil
+}
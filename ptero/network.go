@@ -0,0 +1,72 @@
+package ptero
+
+import (
+	"context"
+	"errors"
+	"strconv"
+)
+
+var ErrAllocationNotFound = errors.New("allocation not found on the server")
+
+type ServerAllocation struct {
+	ID        int     `json:"id"`
+	IP        string  `json:"ip"`
+	IPAlias   *string `json:"ip_alias"`
+	Port      int     `json:"port"`
+	Notes     *string `json:"notes"`
+	IsDefault bool    `json:"is_default"`
+}
+
+func (c *Client) ListAllocations(ctx context.Context, id string) ([]*ServerAllocation, error) {
+	var allocations []*ServerAllocation
+	_, err := c.list(ctx, "/api/client/servers/"+id+"/network/allocations", nil, &allocations)
+
+	return allocations, err
+}
+
+func (c *Client) GetAllocation(ctx context.Context, id string, allocation int) (*ServerAllocation, error) {
+	allocations, err := c.ListAllocations(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range allocations {
+		if a.ID == allocation {
+			return a, nil
+		}
+	}
+
+	return nil, ErrAllocationNotFound
+}
+
+func (c *Client) AssignAllocation(ctx context.Context, id string) (*ServerAllocation, error) {
+	var allocation ServerAllocation
+	if err := c.item(ctx, "POST", "/api/client/servers/"+id+"/network/allocations", nil, &allocation); err != nil {
+		return nil, err
+	}
+
+	return &allocation, nil
+}
+
+func (c *Client) SetAllocationNote(ctx context.Context, id string, allocation int, note string) (*ServerAllocation, error) {
+	var a ServerAllocation
+	body := map[string]string{"notes": note}
+	if err := c.item(ctx, "POST", "/api/client/servers/"+id+"/network/allocations/"+strconv.Itoa(allocation), body, &a); err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+func (c *Client) SetPrimaryAllocation(ctx context.Context, id string, allocation int) (*ServerAllocation, error) {
+	var a ServerAllocation
+	if err := c.item(ctx, "POST", "/api/client/servers/"+id+"/network/allocations/"+strconv.Itoa(allocation)+"/primary", nil, &a); err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+func (c *Client) UnassignAllocation(ctx context.Context, id string, allocation int) error {
+	return c.item(ctx, "DELETE", "/api/client/servers/"+id+"/network/allocations/"+strconv.Itoa(allocation), nil, nil)
+}
@@ -0,0 +1,175 @@
+package ptero
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+type Node struct {
+	ID                 int    `json:"id"`
+	UUID               string `json:"uuid"`
+	Public             bool   `json:"public"`
+	Name               string `json:"name"`
+	Description        string `json:"description"`
+	LocationID         int    `json:"location_id"`
+	FQDN               string `json:"fqdn"`
+	Scheme             string `json:"scheme"`
+	BehindProxy        bool   `json:"behind_proxy"`
+	MaintenanceMode    bool   `json:"maintenance_mode"`
+	Memory             int64  `json:"memory"`
+	MemoryOverallocate int64  `json:"memory_overallocate"`
+	Disk               int64  `json:"disk"`
+	DiskOverallocate   int64  `json:"disk_overallocate"`
+	UploadSize         int64  `json:"upload_size"`
+	DaemonListen       int    `json:"daemon_listen"`
+	DaemonSFTP         int    `json:"daemon_sftp"`
+	DaemonBase         string `json:"daemon_base"`
+	AllocatedResources struct {
+		Memory int64 `json:"memory"`
+		Disk   int64 `json:"disk"`
+	} `json:"allocated_resources"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type NodeDescriptor struct {
+	Name               string `json:"name" validate:"required,max=100"`
+	Description        string `json:"description"`
+	LocationID         int    `json:"location_id" validate:"required,min=1"`
+	Public             bool   `json:"public"`
+	FQDN               string `json:"fqdn" validate:"required,hostname_rfc1123|ip"`
+	Scheme             string `json:"scheme" validate:"required,oneof=http https"`
+	BehindProxy        bool   `json:"behind_proxy"`
+	MaintenanceMode    bool   `json:"maintenance_mode"`
+	Memory             int64  `json:"memory" validate:"min=0"`
+	MemoryOverallocate int64  `json:"memory_overallocate" validate:"min=-1"`
+	Disk               int64  `json:"disk" validate:"min=0"`
+	DiskOverallocate   int64  `json:"disk_overallocate" validate:"min=-1"`
+	UploadSize         int64  `json:"upload_size" validate:"min=1"`
+	DaemonListen       int    `json:"daemon_listen" validate:"min=1,max=65535"`
+	DaemonSFTP         int    `json:"daemon_sftp" validate:"min=1,max=65535"`
+	DaemonBase         string `json:"daemon_base" validate:"required"`
+}
+
+func NewNodeDescriptor() NodeDescriptor {
+	return NodeDescriptor{
+		Public:       true,
+		Scheme:       "https",
+		UploadSize:   100,
+		DaemonListen: 8080,
+		DaemonSFTP:   2022,
+		DaemonBase:   "/var/lib/pterodactyl/volumes",
+	}
+}
+
+func (n *Node) Descriptor() NodeDescriptor {
+	return NodeDescriptor{
+		Name:               n.Name,
+		Description:        n.Description,
+		LocationID:         n.LocationID,
+		Public:             n.Public,
+		FQDN:               n.FQDN,
+		Scheme:             n.Scheme,
+		BehindProxy:        n.BehindProxy,
+		MaintenanceMode:    n.MaintenanceMode,
+		Memory:             n.Memory,
+		MemoryOverallocate: n.MemoryOverallocate,
+		Disk:               n.Disk,
+		DiskOverallocate:   n.DiskOverallocate,
+		UploadSize:         n.UploadSize,
+		DaemonListen:       n.DaemonListen,
+		DaemonSFTP:         n.DaemonSFTP,
+		DaemonBase:         n.DaemonBase,
+	}
+}
+
+type Allocation struct {
+	ID       int    `json:"id"`
+	IP       string `json:"ip"`
+	Alias    string `json:"alias"`
+	Port     int    `json:"port"`
+	Notes    string `json:"notes"`
+	Assigned bool   `json:"assigned"`
+}
+
+type CreateAllocationsDescriptor struct {
+	IP    string   `json:"ip"`
+	Alias string   `json:"alias,omitempty"`
+	Ports []string `json:"ports"`
+}
+
+func (a *Application) ListNodes(ctx context.Context, opts *ListOptions) ([]*Node, *Meta, error) {
+	var nodes []*Node
+	meta, err := a.list(ctx, "/api/application/nodes", opts, &nodes)
+
+	return nodes, meta, err
+}
+
+func (a *Application) GetNode(ctx context.Context, id int) (*Node, error) {
+	var node Node
+	if err := a.item(ctx, "GET", fmt.Sprintf("/api/application/nodes/%d", id), nil, &node); err != nil {
+		return nil, err
+	}
+
+	return &node, nil
+}
+
+func (a *Application) CreateNode(ctx context.Context, fields NodeDescriptor) (*Node, error) {
+	if err := validate.Struct(fields); err != nil {
+		return nil, err
+	}
+
+	var node Node
+	if err := a.item(ctx, "POST", "/api/application/nodes", fields, &node); err != nil {
+		return nil, err
+	}
+
+	return &node, nil
+}
+
+func (a *Application) UpdateNode(ctx context.Context, id int, fields NodeDescriptor) (*Node, error) {
+	if err := validate.Struct(fields); err != nil {
+		return nil, err
+	}
+
+	var node Node
+	if err := a.item(ctx, "PATCH", fmt.Sprintf("/api/application/nodes/%d", id), fields, &node); err != nil {
+		return nil, err
+	}
+
+	return &node, nil
+}
+
+func (a *Application) DeleteNode(ctx context.Context, id int) error {
+	return a.item(ctx, "DELETE", fmt.Sprintf("/api/application/nodes/%d", id), nil, nil)
+}
+
+func (a *Application) GetNodeConfiguration(ctx context.Context, id int) (map[string]interface{}, error) {
+	res, err := a.raw(ctx, "GET", fmt.Sprintf("/api/application/nodes/%d/configuration", id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var model map[string]interface{}
+	if err = json.Unmarshal(res, &model); err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+func (a *Application) ListNodeAllocations(ctx context.Context, node int, opts *ListOptions) ([]*Allocation, *Meta, error) {
+	var allocations []*Allocation
+	meta, err := a.list(ctx, fmt.Sprintf("/api/application/nodes/%d/allocations", node), opts, &allocations)
+
+	return allocations, meta, err
+}
+
+func (a *Application) CreateNodeAllocations(ctx context.Context, node int, fields CreateAllocationsDescriptor) error {
+	return a.item(ctx, "POST", fmt.Sprintf("/api/application/nodes/%d/allocations", node), fields, nil)
+}
+
+func (a *Application) DeleteNodeAllocation(ctx context.Context, node, id int) error {
+	return a.item(ctx, "DELETE", fmt.Sprintf("/api/application/nodes/%d/allocations/%d", node, id), nil, nil)
+}
@@ -0,0 +1,186 @@
+package ptero
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/pteropackages/soar/config"
+	"github.com/pteropackages/soar/http"
+	"github.com/pteropackages/soar/logger"
+)
+
+type Meta = http.Meta
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+
+		return name
+	})
+
+	return v
+}
+
+type ListOptions struct {
+	Page    int
+	PerPage int
+	All     bool
+	Filters map[string]string
+	Include []string
+}
+
+func (o *ListOptions) query() string {
+	if o == nil {
+		return ""
+	}
+
+	query := url.Values{}
+	if o.Page > 0 {
+		query.Set("page", fmt.Sprint(o.Page))
+	}
+
+	if perPage := o.PerPage; perPage > 0 {
+		if perPage > 100 {
+			perPage = 100
+		}
+		query.Set("per_page", fmt.Sprint(perPage))
+	} else if o.All {
+		query.Set("per_page", "100")
+	}
+
+	for k, v := range o.Filters {
+		query.Set("filter["+k+"]", v)
+	}
+
+	if len(o.Include) != 0 {
+		query.Set("include", strings.Join(o.Include, ","))
+	}
+
+	if len(query) == 0 {
+		return ""
+	}
+
+	return "?" + query.Encode()
+}
+
+type base struct {
+	http *http.Client
+}
+
+func (b *base) raw(ctx context.Context, method, path string, body interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(data)
+	}
+
+	req := b.http.Request(method, path, buf).WithContext(ctx)
+	return b.http.Do(req)
+}
+
+func (b *base) item(ctx context.Context, method, path string, body, out interface{}) error {
+	res, err := b.raw(ctx, method, path, body)
+	if err != nil || out == nil {
+		return err
+	}
+
+	var model struct {
+		A json.RawMessage `json:"attributes"`
+	}
+	if err = json.Unmarshal(res, &model); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(model.A, out)
+}
+
+func (b *base) list(ctx context.Context, path string, opts *ListOptions, out interface{}) (*Meta, error) {
+	req := b.http.Request("GET", path+opts.query(), nil).WithContext(ctx)
+
+	var res []byte
+	var err error
+
+	if opts != nil && opts.All {
+		res, err = b.http.DoAll(req)
+	} else {
+		res, err = b.http.Do(req)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeList(res, out)
+}
+
+func decodeList(buf []byte, out interface{}) (*Meta, error) {
+	var model struct {
+		D []struct {
+			A json.RawMessage `json:"attributes"`
+		} `json:"data"`
+		M *Meta `json:"meta"`
+	}
+	if err := json.Unmarshal(buf, &model); err != nil {
+		return nil, err
+	}
+
+	slice := reflect.ValueOf(out).Elem()
+	items := reflect.MakeSlice(slice.Type(), 0, len(model.D))
+	for _, d := range model.D {
+		item := reflect.New(slice.Type().Elem().Elem())
+		if err := json.Unmarshal(d.A, item.Interface()); err != nil {
+			return nil, err
+		}
+
+		items = reflect.Append(items, item)
+	}
+	slice.Set(items)
+
+	return model.M, nil
+}
+
+type Application struct {
+	base
+}
+
+func NewApplication(client *http.Client) *Application {
+	return &Application{base{http: client}}
+}
+
+func NewApplicationWithKey(url, key string) *Application {
+	cfg := &config.Config{Application: config.Auth{URL: url, Key: key}}
+	log := logger.New()
+	log.Quiet = true
+
+	return NewApplication(http.New(cfg, &cfg.Application, log))
+}
+
+type Client struct {
+	base
+}
+
+func NewClient(client *http.Client) *Client {
+	return &Client{base{http: client}}
+}
+
+func NewClientWithKey(url, key string) *Client {
+	cfg := &config.Config{Client: config.Auth{URL: url, Key: key}}
+	log := logger.New()
+	log.Quiet = true
+
+	return NewClient(http.New(cfg, &cfg.Client, log))
+}
@@ -0,0 +1,228 @@
+package ptero
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+type Schedule struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Cron struct {
+		DayOfWeek  string `json:"day_of_week"`
+		DayOfMonth string `json:"day_of_month"`
+		Hour       string `json:"hour"`
+		Minute     string `json:"minute"`
+		Month      string `json:"month"`
+	} `json:"cron"`
+	IsActive       bool            `json:"is_active"`
+	IsProcessing   bool            `json:"is_processing"`
+	OnlyWhenOnline bool            `json:"only_when_online"`
+	LastRunAt      *string         `json:"last_run_at"`
+	NextRunAt      *string         `json:"next_run_at"`
+	CreatedAt      string          `json:"created_at"`
+	UpdatedAt      string          `json:"updated_at"`
+	Tasks          []*ScheduleTask `json:"tasks"`
+}
+
+func (s *Schedule) UnmarshalJSON(data []byte) error {
+	type schedule Schedule
+	var model struct {
+		*schedule
+		R struct {
+			T json.RawMessage `json:"tasks"`
+		} `json:"relationships"`
+	}
+	model.schedule = (*schedule)(s)
+
+	if err := json.Unmarshal(data, &model); err != nil {
+		return err
+	}
+
+	if len(model.R.T) != 0 {
+		if _, err := decodeList(model.R.T, &s.Tasks); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type ScheduleTask struct {
+	ID                int    `json:"id"`
+	SequenceID        int    `json:"sequence_id"`
+	Action            string `json:"action"`
+	Payload           string `json:"payload"`
+	TimeOffset        int    `json:"time_offset"`
+	IsQueued          bool   `json:"is_queued"`
+	ContinueOnFailure bool   `json:"continue_on_failure"`
+	CreatedAt         string `json:"created_at"`
+	UpdatedAt         string `json:"updated_at"`
+}
+
+type ScheduleDescriptor struct {
+	Name           string `json:"name" yaml:"name" validate:"required,max=255"`
+	Minute         string `json:"minute" yaml:"minute" validate:"required"`
+	Hour           string `json:"hour" yaml:"hour" validate:"required"`
+	DayOfMonth     string `json:"day_of_month" yaml:"day_of_month" validate:"required"`
+	Month          string `json:"month" yaml:"month" validate:"required"`
+	DayOfWeek      string `json:"day_of_week" yaml:"day_of_week" validate:"required"`
+	IsActive       bool   `json:"is_active" yaml:"is_active"`
+	OnlyWhenOnline bool   `json:"only_when_online" yaml:"only_when_online"`
+}
+
+func NewScheduleDescriptor() ScheduleDescriptor {
+	return ScheduleDescriptor{
+		Minute:     "*",
+		Hour:       "*",
+		DayOfMonth: "*",
+		Month:      "*",
+		DayOfWeek:  "*",
+		IsActive:   true,
+	}
+}
+
+func (s *Schedule) Descriptor() ScheduleDescriptor {
+	return ScheduleDescriptor{
+		Name:           s.Name,
+		Minute:         s.Cron.Minute,
+		Hour:           s.Cron.Hour,
+		DayOfMonth:     s.Cron.DayOfMonth,
+		Month:          s.Cron.Month,
+		DayOfWeek:      s.Cron.DayOfWeek,
+		IsActive:       s.IsActive,
+		OnlyWhenOnline: s.OnlyWhenOnline,
+	}
+}
+
+type TaskDescriptor struct {
+	Action            string `json:"action" yaml:"action" validate:"required,oneof=command power backup"`
+	Payload           string `json:"payload" yaml:"payload"`
+	TimeOffset        int    `json:"time_offset" yaml:"time_offset" validate:"min=0,max=900"`
+	ContinueOnFailure bool   `json:"continue_on_failure" yaml:"continue_on_failure"`
+}
+
+func (t *ScheduleTask) Descriptor() TaskDescriptor {
+	return TaskDescriptor{
+		Action:            t.Action,
+		Payload:           t.Payload,
+		TimeOffset:        t.TimeOffset,
+		ContinueOnFailure: t.ContinueOnFailure,
+	}
+}
+
+type ScheduleExport struct {
+	ScheduleDescriptor `yaml:",inline"`
+	Tasks              []TaskDescriptor `yaml:"tasks"`
+}
+
+func (s *Schedule) Export() *ScheduleExport {
+	tasks := make([]TaskDescriptor, 0, len(s.Tasks))
+	for _, t := range s.Tasks {
+		tasks = append(tasks, t.Descriptor())
+	}
+
+	return &ScheduleExport{ScheduleDescriptor: s.Descriptor(), Tasks: tasks}
+}
+
+// Validate checks the schedule and all of its tasks, so that an import can be
+// rejected before anything is created.
+func (s *ScheduleExport) Validate() error {
+	if err := validate.Struct(s.ScheduleDescriptor); err != nil {
+		return err
+	}
+
+	for _, t := range s.Tasks {
+		if err := validate.Struct(t); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func schedulePath(id string, schedule int) string {
+	return "/api/client/servers/" + id + "/schedules/" + strconv.Itoa(schedule)
+}
+
+func (c *Client) ListSchedules(ctx context.Context, id string) ([]*Schedule, error) {
+	var schedules []*Schedule
+	_, err := c.list(ctx, "/api/client/servers/"+id+"/schedules", nil, &schedules)
+
+	return schedules, err
+}
+
+func (c *Client) GetSchedule(ctx context.Context, id string, schedule int) (*Schedule, error) {
+	var s Schedule
+	if err := c.item(ctx, "GET", schedulePath(id, schedule), nil, &s); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+func (c *Client) CreateSchedule(ctx context.Context, id string, fields ScheduleDescriptor) (*Schedule, error) {
+	if err := validate.Struct(fields); err != nil {
+		return nil, err
+	}
+
+	var s Schedule
+	if err := c.item(ctx, "POST", "/api/client/servers/"+id+"/schedules", fields, &s); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+func (c *Client) UpdateSchedule(ctx context.Context, id string, schedule int, fields ScheduleDescriptor) (*Schedule, error) {
+	if err := validate.Struct(fields); err != nil {
+		return nil, err
+	}
+
+	var s Schedule
+	if err := c.item(ctx, "POST", schedulePath(id, schedule), fields, &s); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+func (c *Client) DeleteSchedule(ctx context.Context, id string, schedule int) error {
+	return c.item(ctx, "DELETE", schedulePath(id, schedule), nil, nil)
+}
+
+func (c *Client) ExecuteSchedule(ctx context.Context, id string, schedule int) error {
+	return c.item(ctx, "POST", schedulePath(id, schedule)+"/execute", nil, nil)
+}
+
+func (c *Client) CreateScheduleTask(ctx context.Context, id string, schedule int, fields TaskDescriptor) (*ScheduleTask, error) {
+	if err := validate.Struct(fields); err != nil {
+		return nil, err
+	}
+
+	var t ScheduleTask
+	if err := c.item(ctx, "POST", schedulePath(id, schedule)+"/tasks", fields, &t); err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+func (c *Client) UpdateScheduleTask(ctx context.Context, id string, schedule, task int, fields TaskDescriptor) (*ScheduleTask, error) {
+	if err := validate.Struct(fields); err != nil {
+		return nil, err
+	}
+
+	var t ScheduleTask
+	if err := c.item(ctx, "POST", fmt.Sprintf("%s/tasks/%d", schedulePath(id, schedule), task), fields, &t); err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+func (c *Client) DeleteScheduleTask(ctx context.Context, id string, schedule, task int) error {
+	return c.item(ctx, "DELETE", fmt.Sprintf("%s/tasks/%d", schedulePath(id, schedule), task), nil, nil)
+}
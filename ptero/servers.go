@@ -0,0 +1,260 @@
+package ptero
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+type Limits struct {
+	Memory      int64   `json:"memory"`
+	Swap        int64   `json:"swap"`
+	Disk        int64   `json:"disk"`
+	IO          int64   `json:"io"`
+	CPU         int64   `json:"cpu"`
+	Threads     *string `json:"threads"`
+	OOMDisabled bool    `json:"oom_disabled"`
+}
+
+type FeatureLimits struct {
+	Databases   int `json:"databases"`
+	Allocations int `json:"allocations"`
+	Backups     int `json:"backups"`
+}
+
+type Container struct {
+	StartupCommand string                 `json:"startup_command"`
+	Image          string                 `json:"image"`
+	Installed      int                    `json:"installed"`
+	Environment    map[string]interface{} `json:"environment"`
+}
+
+type Server struct {
+	ID            int           `json:"id"`
+	ExternalID    string        `json:"external_id"`
+	UUID          string        `json:"uuid"`
+	Identifier    string        `json:"identifier"`
+	Name          string        `json:"name"`
+	Description   string        `json:"description"`
+	Status        string        `json:"status"`
+	Suspended     bool          `json:"suspended"`
+	Limits        Limits        `json:"limits"`
+	FeatureLimits FeatureLimits `json:"feature_limits"`
+	User          int           `json:"user"`
+	Node          int           `json:"node"`
+	Allocation    int           `json:"allocation"`
+	Nest          int           `json:"nest"`
+	Egg           int           `json:"egg"`
+	Container     Container     `json:"container"`
+	CreatedAt     string        `json:"created_at"`
+	UpdatedAt     string        `json:"updated_at"`
+}
+
+type AllocationDescriptor struct {
+	Default    int   `json:"default"`
+	Additional []int `json:"additional,omitempty"`
+}
+
+type DeployDescriptor struct {
+	Locations   []int    `json:"locations"`
+	DedicatedIP bool     `json:"dedicated_ip"`
+	PortRange   []string `json:"port_range"`
+}
+
+type CreateServerDescriptor struct {
+	Name              string                 `json:"name"`
+	Description       string                 `json:"description,omitempty"`
+	ExternalID        string                 `json:"external_id,omitempty"`
+	User              int                    `json:"user"`
+	Egg               int                    `json:"egg"`
+	DockerImage       string                 `json:"docker_image"`
+	Startup           string                 `json:"startup"`
+	Environment       map[string]interface{} `json:"environment"`
+	Limits            Limits                 `json:"limits"`
+	FeatureLimits     FeatureLimits          `json:"feature_limits"`
+	Allocation        *AllocationDescriptor  `json:"allocation,omitempty"`
+	Deploy            *DeployDescriptor      `json:"deploy,omitempty"`
+	SkipScripts       bool                   `json:"skip_scripts"`
+	StartOnCompletion bool                   `json:"start_on_completion"`
+}
+
+type UpdateServerDetailsDescriptor struct {
+	Name        string  `json:"name"`
+	User        int     `json:"user"`
+	ExternalID  *string `json:"external_id"`
+	Description string  `json:"description"`
+}
+
+type UpdateServerBuildDescriptor struct {
+	Allocation        int           `json:"allocation"`
+	Limits            Limits        `json:"limits"`
+	FeatureLimits     FeatureLimits `json:"feature_limits"`
+	AddAllocations    []int         `json:"add_allocations,omitempty"`
+	RemoveAllocations []int         `json:"remove_allocations,omitempty"`
+}
+
+type UpdateServerStartupDescriptor struct {
+	Startup     string                 `json:"startup"`
+	Environment map[string]interface{} `json:"environment"`
+	Egg         int                    `json:"egg"`
+	Image       string                 `json:"image"`
+	SkipScripts bool                   `json:"skip_scripts"`
+}
+
+func (s *Server) DetailsDescriptor() UpdateServerDetailsDescriptor {
+	fields := UpdateServerDetailsDescriptor{
+		Name:        s.Name,
+		User:        s.User,
+		Description: s.Description,
+	}
+	if s.ExternalID != "" {
+		id := s.ExternalID
+		fields.ExternalID = &id
+	}
+
+	return fields
+}
+
+func (s *Server) BuildDescriptor() UpdateServerBuildDescriptor {
+	return UpdateServerBuildDescriptor{
+		Allocation:    s.Allocation,
+		Limits:        s.Limits,
+		FeatureLimits: s.FeatureLimits,
+	}
+}
+
+func (s *Server) StartupDescriptor() UpdateServerStartupDescriptor {
+	env := make(map[string]interface{}, len(s.Container.Environment))
+	for k, v := range s.Container.Environment {
+		env[k] = v
+	}
+
+	return UpdateServerStartupDescriptor{
+		Startup:     s.Container.StartupCommand,
+		Environment: env,
+		Egg:         s.Egg,
+		Image:       s.Container.Image,
+	}
+}
+
+// ApplyEggDefaults fills in the docker image, startup command and any missing
+// environment variables from the egg's defaults.
+func (a *Application) ApplyEggDefaults(ctx context.Context, nest int, fields *CreateServerDescriptor) error {
+	egg, err := a.GetNestEgg(ctx, nest, fields.Egg)
+	if err != nil {
+		return err
+	}
+
+	if fields.DockerImage == "" {
+		fields.DockerImage = egg.DockerImage
+		if fields.DockerImage == "" && len(egg.DockerImages) != 0 {
+			names := make([]string, 0, len(egg.DockerImages))
+			for name := range egg.DockerImages {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			fields.DockerImage = egg.DockerImages[names[0]]
+		}
+	}
+	if fields.Startup == "" {
+		fields.Startup = egg.Startup
+	}
+
+	vars, err := a.ListNestEggVariables(ctx, nest, fields.Egg)
+	if err != nil {
+		return err
+	}
+
+	if fields.Environment == nil {
+		fields.Environment = map[string]interface{}{}
+	}
+	for _, v := range vars {
+		if _, ok := fields.Environment[v.EnvVariable]; !ok {
+			fields.Environment[v.EnvVariable] = v.DefaultValue
+		}
+	}
+
+	return nil
+}
+
+func (a *Application) ListServers(ctx context.Context, opts *ListOptions) ([]*Server, *Meta, error) {
+	var servers []*Server
+	meta, err := a.list(ctx, "/api/application/servers", opts, &servers)
+
+	return servers, meta, err
+}
+
+func (a *Application) GetServer(ctx context.Context, id int) (*Server, error) {
+	var server Server
+	if err := a.item(ctx, "GET", fmt.Sprintf("/api/application/servers/%d", id), nil, &server); err != nil {
+		return nil, err
+	}
+
+	return &server, nil
+}
+
+func (a *Application) GetServerExternal(ctx context.Context, id string) (*Server, error) {
+	var server Server
+	if err := a.item(ctx, "GET", "/api/application/servers/external/"+id, nil, &server); err != nil {
+		return nil, err
+	}
+
+	return &server, nil
+}
+
+func (a *Application) CreateServer(ctx context.Context, fields CreateServerDescriptor) (*Server, error) {
+	var server Server
+	if err := a.item(ctx, "POST", "/api/application/servers", fields, &server); err != nil {
+		return nil, err
+	}
+
+	return &server, nil
+}
+
+func (a *Application) UpdateServerDetails(ctx context.Context, id int, fields UpdateServerDetailsDescriptor) (*Server, error) {
+	var server Server
+	if err := a.item(ctx, "PATCH", fmt.Sprintf("/api/application/servers/%d/details", id), fields, &server); err != nil {
+		return nil, err
+	}
+
+	return &server, nil
+}
+
+func (a *Application) UpdateServerBuild(ctx context.Context, id int, fields UpdateServerBuildDescriptor) (*Server, error) {
+	var server Server
+	if err := a.item(ctx, "PATCH", fmt.Sprintf("/api/application/servers/%d/build", id), fields, &server); err != nil {
+		return nil, err
+	}
+
+	return &server, nil
+}
+
+func (a *Application) UpdateServerStartup(ctx context.Context, id int, fields UpdateServerStartupDescriptor) (*Server, error) {
+	var server Server
+	if err := a.item(ctx, "PATCH", fmt.Sprintf("/api/application/servers/%d/startup", id), fields, &server); err != nil {
+		return nil, err
+	}
+
+	return &server, nil
+}
+
+func (a *Application) SuspendServer(ctx context.Context, id int) error {
+	return a.item(ctx, "POST", fmt.Sprintf("/api/application/servers/%d/suspend", id), nil, nil)
+}
+
+func (a *Application) UnsuspendServer(ctx context.Context, id int) error {
+	return a.item(ctx, "POST", fmt.Sprintf("/api/application/servers/%d/unsuspend", id), nil, nil)
+}
+
+func (a *Application) ReinstallServer(ctx context.Context, id int) error {
+	return a.item(ctx, "POST", fmt.Sprintf("/api/application/servers/%d/reinstall", id), nil, nil)
+}
+
+func (a *Application) DeleteServer(ctx context.Context, id int, force bool) error {
+	path := fmt.Sprintf("/api/application/servers/%d", id)
+	if force {
+		path += "/force"
+	}
+
+	return a.item(ctx, "DELETE", path, nil, nil)
+}
@@ -0,0 +1,53 @@
+package ptero
+
+import (
+	"context"
+	"encoding/json"
+)
+
+type StartupVariable struct {
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	EnvVariable  string `json:"env_variable"`
+	DefaultValue string `json:"default_value"`
+	ServerValue  string `json:"server_value"`
+	IsEditable   bool   `json:"is_editable"`
+	Rules        string `json:"rules"`
+}
+
+type StartupMeta struct {
+	StartupCommand    string            `json:"startup_command"`
+	DockerImages      map[string]string `json:"docker_images"`
+	RawStartupCommand string            `json:"raw_startup_command"`
+}
+
+func (c *Client) GetStartup(ctx context.Context, id string) ([]*StartupVariable, *StartupMeta, error) {
+	res, err := c.raw(ctx, "GET", "/api/client/servers/"+id+"/startup", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var vars []*StartupVariable
+	if _, err = decodeList(res, &vars); err != nil {
+		return nil, nil, err
+	}
+
+	var model struct {
+		M *StartupMeta `json:"meta"`
+	}
+	if err = json.Unmarshal(res, &model); err != nil {
+		return nil, nil, err
+	}
+
+	return vars, model.M, nil
+}
+
+func (c *Client) SetStartupVariable(ctx context.Context, id, key, value string) (*StartupVariable, error) {
+	var v StartupVariable
+	body := map[string]string{"key": key, "value": value}
+	if err := c.item(ctx, "PUT", "/api/client/servers/"+id+"/startup/variable", body, &v); err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
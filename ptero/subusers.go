@@ -0,0 +1,43 @@
+package ptero
+
+import "context"
+
+type Subuser struct {
+	UUID        string   `json:"uuid"`
+	Username    string   `json:"username"`
+	Email       string   `json:"email"`
+	Image       string   `json:"image"`
+	TwoFactor   bool     `json:"2fa_enabled"`
+	CreatedAt   string   `json:"created_at"`
+	Permissions []string `json:"permissions"`
+}
+
+func (c *Client) ListSubusers(ctx context.Context, id string) ([]*Subuser, error) {
+	var users []*Subuser
+	_, err := c.list(ctx, "/api/client/servers/"+id+"/users", nil, &users)
+
+	return users, err
+}
+
+func (c *Client) GetSubuser(ctx context.Context, id, uuid string) (*Subuser, error) {
+	var user Subuser
+	if err := c.item(ctx, "GET", "/api/client/servers/"+id+"/users/"+uuid, nil, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (c *Client) AddSubuser(ctx context.Context, id, email string, permissions []string) (*Subuser, error) {
+	var user Subuser
+	body := map[string]interface{}{"email": email, "permissions": permissions}
+	if err := c.item(ctx, "POST", "/api/client/servers/"+id+"/users", body, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (c *Client) RemoveSubuser(ctx context.Context, id, uuid string) error {
+	return c.item(ctx, "DELETE", "/api/client/servers/"+id+"/users/"+uuid, nil, nil)
+}
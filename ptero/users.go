@@ -0,0 +1,106 @@
+package ptero
+
+import (
+	"context"
+	"fmt"
+)
+
+type User struct {
+	ID         int    `json:"id"`
+	ExternalID string `json:"external_id"`
+	UUID       string `json:"uuid"`
+	Username   string `json:"username"`
+	Email      string `json:"email"`
+	FirstName  string `json:"first_name"`
+	LastName   string `json:"last_name"`
+	Language   string `json:"language"`
+	RootAdmin  bool   `json:"root_admin"`
+	TwoFactor  bool   `json:"2fa"`
+	CreatedAt  string `json:"created_at"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+type CreateUserDescriptor struct {
+	Username   string `json:"username"`
+	Email      string `json:"email"`
+	ExternalID string `json:"external_id,omitempty"`
+	FirstName  string `json:"first_name"`
+	LastName   string `json:"last_name"`
+	RootAdmin  bool   `json:"root_admin,omitempty"`
+	Password   string `json:"password,omitempty"`
+}
+
+type UpdateUserDescriptor struct {
+	Username   string  `json:"username"`
+	Email      string  `json:"email"`
+	ExternalID *string `json:"external_id"`
+	FirstName  string  `json:"first_name"`
+	LastName   string  `json:"last_name"`
+	Language   string  `json:"language,omitempty"`
+	RootAdmin  bool    `json:"root_admin"`
+	Password   string  `json:"password,omitempty"`
+}
+
+func (u *User) UpdateDescriptor() UpdateUserDescriptor {
+	fields := UpdateUserDescriptor{
+		Username:  u.Username,
+		Email:     u.Email,
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+		Language:  u.Language,
+		RootAdmin: u.RootAdmin,
+	}
+	if u.ExternalID != "" {
+		id := u.ExternalID
+		fields.ExternalID = &id
+	}
+
+	return fields
+}
+
+func (a *Application) ListUsers(ctx context.Context, opts *ListOptions) ([]*User, *Meta, error) {
+	var users []*User
+	meta, err := a.list(ctx, "/api/application/users", opts, &users)
+
+	return users, meta, err
+}
+
+func (a *Application) GetUser(ctx context.Context, id int) (*User, error) {
+	var user User
+	if err := a.item(ctx, "GET", fmt.Sprintf("/api/application/users/%d", id), nil, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (a *Application) GetUserExternal(ctx context.Context, id string) (*User, error) {
+	var user User
+	if err := a.item(ctx, "GET", "/api/application/users/external/"+id, nil, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (a *Application) CreateUser(ctx context.Context, fields CreateUserDescriptor) (*User, error) {
+	var user User
+	if err := a.item(ctx, "POST", "/api/application/users", fields, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (a *Application) UpdateUser(ctx context.Context, id int, fields UpdateUserDescriptor) (*User, error) {
+	var user User
+	if err := a.item(ctx, "PATCH", fmt.Sprintf("/api/application/users/%d", id), fields, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (a *Application) DeleteUser(ctx context.Context, id int) error {
+	return a.item(ctx, "DELETE", fmt.Sprintf("/api/application/users/%d", id), nil, nil)
+}
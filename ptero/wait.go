@@ -0,0 +1,113 @@
+package ptero
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/pteropackages/soar/http"
+)
+
+const pollInterval = 2 * time.Second
+
+var ErrWaitTimeout = errors.New("timed out waiting for the server state")
+
+func poll(ctx context.Context, check func() (bool, error)) error {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		done, err := check()
+		if ctx.Err() == context.DeadlineExceeded {
+			return ErrWaitTimeout
+		}
+		if err != nil || done {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return ErrWaitTimeout
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Client) WaitForState(ctx context.Context, id, state string) error {
+	return poll(ctx, func() (bool, error) {
+		if state == "installed" {
+			server, err := c.GetServer(ctx, id)
+			if err != nil {
+				return false, err
+			}
+
+			return !server.IsInstalling, nil
+		}
+
+		current, err := c.currentState(ctx, id)
+		return current == state, err
+	})
+}
+
+// WaitForRestart waits for the server to leave the running state and then reach it
+// again, since the server is usually still running when a restart is sent.
+func (c *Client) WaitForRestart(ctx context.Context, id string) error {
+	err := poll(ctx, func() (bool, error) {
+		current, err := c.currentState(ctx, id)
+		return current != "" && current != "running", err
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.WaitForState(ctx, id, "running")
+}
+
+// currentState returns the power state of the server, or an empty string if the
+// state is unavailable.
+func (c *Client) currentState(ctx context.Context, id string) (string, error) {
+	res, err := c.GetServerResources(ctx, id)
+	if err != nil {
+		// resources are unavailable while the server is installing or transferring
+		var e *http.APIError
+		if errors.As(err, &e) && e.Status == 409 {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	return res.CurrentState, nil
+}
+
+func (a *Application) WaitForInstall(ctx context.Context, id int) error {
+	return poll(ctx, func() (bool, error) {
+		server, err := a.GetServer(ctx, id)
+		if err != nil {
+			return false, err
+		}
+
+		switch server.Status {
+		case "installing":
+			return false, nil
+		case "install_failed", "reinstall_failed":
+			return false, errors.New("the server failed to install")
+		default:
+			return true, nil
+		}
+	})
+}
+
+func (a *Application) WaitForSuspension(ctx context.Context, id int) error {
+	return poll(ctx, func() (bool, error) {
+		server, err := a.GetServer(ctx, id)
+		if err != nil {
+			return false, err
+		}
+
+		return server.Suspended, nil
+	})
+}
@@ -0,0 +1,208 @@
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pteropackages/soar/http"
+	"github.com/pteropackages/soar/logger"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func ApplyBulkFlags(cmd *cobra.Command) {
+	cmd.Flags().String("selector", "", "run on all resources matching the selector (key=value,...)")
+	cmd.Flags().String("from-file", "", "run on the ids listed in a file ('-' for stdin)")
+	cmd.Flags().Int("parallel", 1, "the number of resources to run on at once")
+}
+
+// IsBulk reports whether the command should run on multiple resources instead of
+// the one given in the arguments. A '-' argument reads the IDs from stdin.
+func IsBulk(flags *pflag.FlagSet, args []string) bool {
+	selector, _ := flags.GetString("selector")
+	file, _ := flags.GetString("from-file")
+
+	return selector != "" || file != "" || (len(args) != 0 && args[0] == "-")
+}
+
+// Selector matches resources by their fields. Keys are the JSON field names of the
+// resource, using dots for nested fields (e.g. container.image).
+type Selector map[string]string
+
+// ParseBulkFlags returns either the selector or the IDs read from the file or
+// stdin, depending on which was given.
+func ParseBulkFlags(flags *pflag.FlagSet, args []string) (Selector, []string, error) {
+	selector, _ := flags.GetString("selector")
+	file, _ := flags.GetString("from-file")
+	stdin := len(args) != 0 && args[0] == "-"
+	if len(args) > 1 || (len(args) == 1 && !stdin) {
+		return nil, nil, errors.New("ids can't be given as arguments with the selector or from-file flags")
+	}
+
+	if selector != "" {
+		if file != "" || stdin {
+			return nil, nil, errors.New("the selector flag can't be used with a file or stdin")
+		}
+
+		s, err := ParseSelector(selector)
+		return s, nil, err
+	}
+
+	if file != "" && stdin {
+		return nil, nil, errors.New("the from-file flag can't be used with stdin")
+	}
+	if stdin {
+		file = "-"
+	}
+
+	ids, err := ReadIDs(file)
+	return nil, ids, err
+}
+
+func ParseSelector(s string) (Selector, error) {
+	selector := Selector{}
+	for _, part := range strings.Split(s, ",") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, fmt.Errorf("invalid selector '%s' (expected key=value)", part)
+		}
+
+		selector[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+
+	return selector, nil
+}
+
+// Matches reports whether the fields of v match every key in the selector. Keys
+// in aliases are replaced with their field names before matching.
+func (s Selector) Matches(v interface{}, aliases map[string]string) (bool, error) {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return false, err
+	}
+
+	var data interface{}
+	if err = json.Unmarshal(buf, &data); err != nil {
+		return false, err
+	}
+
+	for key, want := range s {
+		path := key
+		if alias, ok := aliases[key]; ok {
+			path = alias
+		}
+
+		value := data
+		for _, name := range strings.Split(path, ".") {
+			m, ok := value.(map[string]interface{})
+			if !ok {
+				return false, fmt.Errorf("unknown selector key '%s'", key)
+			}
+			if value, ok = m[name]; !ok {
+				return false, fmt.Errorf("unknown selector key '%s'", key)
+			}
+		}
+
+		if formatValue(value) != want {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// formatValue formats a decoded JSON value for matching. Numbers are formatted
+// without exponents so that large IDs and limits match as they are written.
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// ReadIDs reads one ID per line from the file, or stdin if the path is '-'. Empty
+// lines and lines starting with '#' are skipped.
+func ReadIDs(path string) ([]string, error) {
+	var buf []byte
+	var err error
+
+	if path == "-" {
+		buf, err = io.ReadAll(os.Stdin)
+	} else {
+		buf, err = SafeReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, line := range strings.Split(string(buf), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			ids = append(ids, line)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil, errors.New("no ids were given")
+	}
+
+	return ids, nil
+}
+
+// RunBulk calls fn for each of the names with up to parallel calls at once, and
+// logs the result of each call followed by a summary. It returns false if any of
+// the calls failed.
+func RunBulk(log *logger.Logger, kind string, names []string, parallel int, fn func(i int) error) bool {
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, parallel)
+	failed, dry := 0, 0
+
+	for i := range names {
+		i := i
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func() {
+			defer wg.Done()
+			err := fn(i)
+			<-sem
+
+			mu.Lock()
+			defer mu.Unlock()
+			if errors.Is(err, http.ErrDryRun) {
+				dry++
+				log.Line("%s %s: dry run", kind, names[i])
+			} else if err != nil {
+				failed++
+				log.Error("%s %s: %s", kind, names[i], err)
+			} else {
+				log.Line("%s %s: ok", kind, names[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	if dry != 0 {
+		log.Line("\n%d shown, %d failed", dry, failed)
+	} else {
+		log.Line("\n%d succeeded, %d failed", len(names)-failed, failed)
+	}
+
+	return failed == 0
+}
@@ -0,0 +1,69 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/pteropackages/soar/logger"
+)
+
+func TestSelectorMatches(t *testing.T) {
+	type limits struct {
+		Memory int64 `json:"memory"`
+	}
+	type resource struct {
+		ID          int     `json:"id"`
+		Name        string  `json:"name"`
+		Description *string `json:"description"`
+		Suspended   bool    `json:"suspended"`
+		Limits      limits  `json:"limits"`
+	}
+
+	v := resource{ID: 7, Name: "survival", Limits: limits{Memory: 1000000}}
+
+	tests := []struct {
+		name     string
+		selector Selector
+		aliases  map[string]string
+		want     bool
+		err      bool
+	}{
+		{name: "string", selector: Selector{"name": "survival"}, want: true},
+		{name: "mismatch", selector: Selector{"name": "creative"}, want: false},
+		{name: "number", selector: Selector{"id": "7"}, want: true},
+		{name: "large number", selector: Selector{"limits.memory": "1000000"}, want: true},
+		{name: "bool", selector: Selector{"suspended": "false"}, want: true},
+		{name: "null", selector: Selector{"description": ""}, want: true},
+		{name: "alias", selector: Selector{"memory": "1000000"}, aliases: map[string]string{"memory": "limits.memory"}, want: true},
+		{name: "all keys", selector: Selector{"name": "survival", "id": "8"}, want: false},
+		{name: "unknown key", selector: Selector{"owner": "1"}, err: true},
+		{name: "unknown nested key", selector: Selector{"name.first": "a"}, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.selector.Matches(v, tt.aliases)
+			if (err != nil) != tt.err {
+				t.Fatalf("Matches() error = %v, want error %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunBulk(t *testing.T) {
+	log := logger.New()
+	log.Quiet = true
+	log.UseColor = false
+
+	names := []string{"1", "2", "3", "4", "5", "6", "7", "8"}
+	ok := RunBulk(log, "server", names, 4, func(i int) error {
+		// requests log through the shared logger from each worker
+		log.Ignore().Info("request %s", names[i])
+		return nil
+	})
+	if !ok {
+		t.Error("RunBulk() = false, want true")
+	}
+}
@@ -0,0 +1,45 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func ApplyConfirmFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolP("yes", "y", false, "skip the confirmation prompt")
+}
+
+// Confirm prompts the user to type the answer before a destructive action is run.
+// The prompt is skipped with the yes or dry-run flags, and refused if stdin is not
+// a terminal, so that scripts have to opt in with the yes flag.
+func Confirm(flags *pflag.FlagSet, prompt, answer string) error {
+	if yes, _ := flags.GetBool("yes"); yes {
+		return nil
+	}
+	if dry, _ := flags.GetBool("dry-run"); dry {
+		return nil
+	}
+
+	if !IsTerminal(os.Stdin) {
+		return errors.New("stdin is not a terminal, run the command with --yes to confirm")
+	}
+
+	fmt.Fprintf(os.Stderr, "%s\ntype '%s' to confirm: ", prompt, answer)
+	line, err := stdin.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		fmt.Fprintln(os.Stderr)
+		return errors.New("confirmation cancelled")
+	}
+
+	if strings.TrimSpace(line) != answer {
+		return errors.New("confirmation did not match, nothing was changed")
+	}
+
+	return nil
+}
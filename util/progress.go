@@ -0,0 +1,84 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pteropackages/soar/http"
+)
+
+const progressWidth = 30
+
+// Progress draws a transfer progress bar on stderr. Nothing is drawn when stderr
+// is not a terminal or quiet mode is enabled.
+type Progress struct {
+	name    string
+	offset  int64
+	current int64
+	total   int64
+	start   time.Time
+	drawn   time.Time
+	enabled bool
+	done    bool
+}
+
+func NewProgress(name string, quiet bool) *Progress {
+	return &Progress{name: name, enabled: !quiet && IsTerminal(os.Stderr)}
+}
+
+func (p *Progress) Start(offset, total int64) {
+	p.offset = offset
+	p.current = offset
+	p.total = total
+	p.start = time.Now()
+}
+
+func (p *Progress) Write(b []byte) (int, error) {
+	p.current += int64(len(b))
+	if p.total > 0 && p.current >= p.total {
+		p.Done()
+	} else if p.enabled && time.Since(p.drawn) >= 100*time.Millisecond {
+		p.draw()
+	}
+
+	return len(b), nil
+}
+
+func (p *Progress) Done() {
+	if !p.enabled || p.done || p.start.IsZero() {
+		return
+	}
+
+	p.done = true
+	p.draw()
+	os.Stderr.WriteString("\n")
+}
+
+func (p *Progress) draw() {
+	p.drawn = time.Now()
+
+	rate := 0.0
+	if elapsed := time.Since(p.start).Seconds(); elapsed > 0 {
+		rate = float64(p.current-p.offset) / elapsed
+	}
+
+	parts := []string{p.name}
+	if p.total > 0 {
+		filled := int(float64(progressWidth) * float64(p.current) / float64(p.total))
+		if filled > progressWidth {
+			filled = progressWidth
+		}
+
+		parts = append(parts,
+			"["+strings.Repeat("=", filled)+strings.Repeat(" ", progressWidth-filled)+"]",
+			fmt.Sprintf("%3d%%", p.current*100/p.total),
+			http.FormatBytes(float64(p.current))+" / "+http.FormatBytes(float64(p.total)))
+	} else {
+		parts = append(parts, http.FormatBytes(float64(p.current)))
+	}
+	parts = append(parts, "("+http.FormatBytes(rate)+"/s)")
+
+	os.Stderr.WriteString("\r\x1b[K" + strings.Join(parts, " "))
+}
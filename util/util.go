@@ -1,19 +1,28 @@
 package util
 
 import (
+	"bufio"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/pteropackages/soar/input"
+	"github.com/pteropackages/soar/ptero"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
 )
 
 func ApplyDefaultFlags(cmd *cobra.Command) {
 	cmd.Flags().Bool("debug", false, "print debug logs")
 	cmd.Flags().Bool("no-color", false, "disable ansi color codes")
 	cmd.Flags().BoolP("global", "g", false, "use the global config")
+	cmd.Flags().String("profile", "", "the config profile to use")
 	cmd.Flags().BoolP("quiet", "q", false, "only print necessary logs")
 
 	cmd.Flags().BoolP("retry-ratelimit", "r", false, "retry request on ratelimit")
@@ -24,6 +33,8 @@ func ApplyDefaultFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolP("no-parse-errors", "E", false, "don't parse the response errors")
 	cmd.Flags().BoolP("parse-indent", "i", false, "indent the response body")
 	cmd.Flags().BoolP("no-parse-indent", "I", false, "don't indent the response body")
+	cmd.Flags().StringP("output", "o", "", "the output format (json, yaml, table, csv, wide, go-template=...)")
+	cmd.Flags().Bool("dry-run", false, "print the requests that would change the panel without sending them")
 }
 
 func ApplyDataFlags(cmd *cobra.Command) {
@@ -35,6 +46,110 @@ func ApplyDataFlags(cmd *cobra.Command) {
 func ApplyFilterFlags(cmd *cobra.Command) {
 	cmd.Flags().Int("page", 0, "the page to request from")
 	cmd.Flags().Int("per-page", 0, "the number of results to return")
+	cmd.Flags().Bool("all", false, "fetch all pages of results")
+}
+
+func ApplyWaitFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("wait", false, "wait for the action to complete")
+	cmd.Flags().Duration("timeout", 5*time.Minute, "the maximum time to wait for")
+}
+
+func ParseListOptions(flags *pflag.FlagSet) *ptero.ListOptions {
+	page, _ := flags.GetInt("page")
+	perPage, _ := flags.GetInt("per-page")
+	all, _ := flags.GetBool("all")
+
+	return &ptero.ListOptions{
+		Page:    page,
+		PerPage: perPage,
+		All:     all,
+		Filters: map[string]string{},
+	}
+}
+
+func ReadDataFlags(flags *pflag.FlagSet, def input.Definition, v interface{}) error {
+	return readDataFlags(flags, def, v, input.Marshal)
+}
+
+func ReadPartialDataFlags(flags *pflag.FlagSet, def input.Definition, v interface{}) error {
+	return readDataFlags(flags, def, v, input.MarshalPartial)
+}
+
+func readDataFlags(flags *pflag.FlagSet, def input.Definition, v interface{}, marshal func(input.Definition, map[string]string) ([]byte, error)) error {
+	var payload []byte
+	data, _ := flags.GetString("data")
+	file, _ := flags.GetString("data-file")
+	js, _ := flags.GetString("data-json")
+
+	switch {
+	case data != "":
+		m, err := input.Parse(data)
+		if err != nil {
+			return fmt.Errorf("failed to parse data input: %v", err)
+		}
+
+		payload, err = marshal(def, m)
+		if err != nil {
+			return fmt.Errorf("failed to parse data input: %v", err)
+		}
+	case file != "":
+		buf, err := SafeReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		payload = buf
+	case js != "":
+		payload = []byte(js)
+	default:
+		return errors.New("no data source provided; '--data', '--data-file' or '--data-json' must be specified")
+	}
+
+	if err := json.Unmarshal(payload, v); err != nil {
+		return fmt.Errorf("failed to parse json input: %v", err)
+	}
+
+	return nil
+}
+
+func ParseKeyValues(values []string) (map[string]string, error) {
+	m := make(map[string]string, len(values))
+	for _, v := range values {
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid key-value pair '%s' (expected key=value)", v)
+		}
+
+		m[parts[0]] = parts[1]
+	}
+
+	return m, nil
+}
+
+func IsTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+var stdin = bufio.NewReader(os.Stdin)
+
+func ReadPassword(prompt string) (string, error) {
+	if !IsTerminal(os.Stdin) {
+		line, err := stdin.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return "", errors.New("failed to read password from stdin")
+		}
+
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	buf, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(buf), nil
 }
 
 func SafeReadFile(path string) ([]byte, error) {
@@ -106,11 +221,3 @@ func RequireArgsOverflow(input, required []string, overflow int) error {
 
 	return nil
 }
-
-func ValidateSchema(in []byte, schema interface{}) ([]byte, error) {
-	if err := json.Unmarshal(in, &schema); err != nil {
-		return nil, err
-	}
-
-	return json.Marshal(schema)
-}